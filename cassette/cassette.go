@@ -0,0 +1,321 @@
+// Package cassette 提供录制和回放 HTTP 交互的 http.RoundTripper
+//
+// 录制时请求被发送到真实服务, 交互在去除 API key 和邮箱后写入 YAML 文件;
+// 回放时按方法, 路径, 查询参数和请求体匹配已录制的交互, 不访问网络.
+// 通过 client.WithTransport 接入:
+//
+//	rec, err := cassette.New("testdata/cassettes/search.yaml", cassette.Options{
+//		Mode:         cassette.ModeAuto,
+//		Secrets:      []string{apiKey},
+//		IgnoreFields: []string{"metadata.timestamp"},
+//	})
+//	defer rec.Stop()
+//	c, err := client.NewMemoryClient(client.ClientOptions{APIKey: apiKey}, client.WithTransport(rec))
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Version 是 cassette 文件格式的版本
+const Version = 1
+
+// Mode 决定 Recorder 录制还是回放
+type Mode int
+
+const (
+	// ModeReplay 只回放, 没有匹配的交互或文件不存在时返回错误
+	ModeReplay Mode = iota
+	// ModeRecord 总是访问真实服务并在 Stop 时覆盖 cassette 文件
+	ModeRecord
+	// ModeAuto 文件存在时回放, 否则录制
+	ModeAuto
+)
+
+// String 返回模式名称
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModeAuto:
+		return "auto"
+	}
+	return "unknown"
+}
+
+// ParseMode 解析 replay, record 或 auto, 空字符串视为 auto
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "", "auto":
+		return ModeAuto, nil
+	}
+	return 0, errors.Errorf("unknown cassette mode %q, expected replay, record or auto", s)
+}
+
+// Request 是录制的请求
+type Request struct {
+	Method  string      `yaml:"method"`
+	URL     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// Response 是录制的响应
+type Response struct {
+	Status  int         `yaml:"status"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// Interaction 是一次请求和它的响应
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+// Cassette 是 cassette 文件的内容
+// Values 保存录制时的测试参数, 例如内存 ID, 使回放时不需要设置同样的环境变量
+type Cassette struct {
+	Version      int               `yaml:"version"`
+	Values       map[string]string `yaml:"values,omitempty"`
+	Interactions []*Interaction    `yaml:"interactions"`
+}
+
+// Load 读取 cassette 文件
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cassette")
+	}
+
+	var c Cassette
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse cassette %s", path)
+	}
+	if c.Version != Version {
+		return nil, errors.Errorf("unsupported cassette version %d in %s", c.Version, path)
+	}
+	return &c, nil
+}
+
+// Save 将 cassette 写入文件, 必要时创建目录
+func (c *Cassette) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cassette")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "failed to create cassette directory")
+	}
+	return errors.Wrap(os.WriteFile(path, data, 0o644), "failed to write cassette")
+}
+
+// Options 配置 Recorder
+type Options struct {
+	Mode Mode
+	// Transport 是录制时使用的真实 transport, 默认为 http.DefaultTransport
+	Transport http.RoundTripper
+	// Secrets 是录制时需要从 URL, 请求头和请求体中去除的字符串, 例如 API key
+	Secrets []string
+	// IgnoreFields 是匹配 JSON 请求体时忽略的字段路径, 例如 "metadata.timestamp"
+	// 路径中的数组会被展开, "*" 匹配任意键. Matcher 不为空时不使用
+	IgnoreFields []string
+	// Matcher 判断请求是否与录制的请求匹配, 默认为 DefaultMatcher(IgnoreFields...)
+	Matcher Matcher
+	// AllowRepeat 为 true 时, 匹配的交互都已使用后重复回放最后一个, 用于轮询次数不固定的测试.
+	// 默认每个交互只回放一次, 多出的请求返回错误
+	AllowRepeat bool
+}
+
+// Recorder 是录制或回放 cassette 的 http.RoundTripper, 可以并发使用
+type Recorder struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+	scrubber  *scrubber
+	matcher   Matcher
+	repeat    bool
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New 创建 Recorder, 回放模式下立即读取 cassette 文件
+func New(path string, opts Options) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		transport: opts.Transport,
+		scrubber:  newScrubber(opts.Secrets),
+		matcher:   opts.Matcher,
+		repeat:    opts.AllowRepeat,
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+	if r.matcher == nil {
+		r.matcher = DefaultMatcher(opts.IgnoreFields...)
+	}
+
+	switch opts.Mode {
+	case ModeRecord:
+		r.recording = true
+	case ModeAuto:
+		_, err := os.Stat(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "failed to stat cassette")
+		}
+		r.recording = os.IsNotExist(err)
+	case ModeReplay:
+	default:
+		return nil, errors.Errorf("unknown cassette mode %d", opts.Mode)
+	}
+
+	if r.recording {
+		r.cassette = &Cassette{Version: Version}
+		return r, nil
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	r.cassette = c
+	r.used = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Recording 报告 Recorder 是否在录制
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Value 返回名为 name 的测试参数. 录制时保存并返回 live, 回放时返回录制时保存的值
+func (r *Recorder) Value(name, live string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording {
+		return r.cassette.Values[name]
+	}
+	if r.cassette.Values == nil {
+		r.cassette.Values = make(map[string]string)
+	}
+	r.cassette.Values[name] = live
+	return live
+}
+
+// Stop 结束录制并写入 cassette 文件, 回放时什么也不做
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := r.scrubber.request(req, body)
+
+	if !r.recording {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request:  recorded,
+		Response: r.scrubber.response(resp, respBody),
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay 返回第一个未使用的匹配交互; 匹配的交互都已使用时返回错误,
+// 设置了 AllowRepeat 时重复最后一个
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matcher(recorded, interaction.Request) {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if last < 0 {
+		return nil, errors.Errorf("cassette %s: no recorded interaction for %s %s", r.path, recorded.Method, recorded.URL)
+	}
+	if r.used[last] && !r.repeat {
+		return nil, errors.Errorf("cassette %s: all recorded interactions for %s %s have been replayed", r.path, recorded.Method, recorded.URL)
+	}
+	r.used[last] = true
+
+	recordedResp := r.cassette.Interactions[last].Response
+	header := recordedResp.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.Status, http.StatusText(recordedResp.Status)),
+		StatusCode:    recordedResp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recordedResp.Body)),
+		ContentLength: int64(len(recordedResp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody 读取请求体并恢复它, 以便真实 transport 再次读取
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
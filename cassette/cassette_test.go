@@ -0,0 +1,155 @@
+package cassette_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/cassette"
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/mem0test"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+const liveKey = "m0-live0123456789abcdef"
+
+func newClient(t *testing.T, host string, rec *cassette.Recorder) *client.MemoryClient {
+	t.Helper()
+
+	mem0, err := client.NewMemoryClient(client.ClientOptions{APIKey: liveKey, Host: host}, client.WithTransport(rec))
+	require.NoError(t, err)
+	return mem0
+}
+
+// session 添加一条带时间戳的内存并搜索它, 录制和回放执行相同的调用
+func session(t *testing.T, mem0 *client.MemoryClient) []types.Memory {
+	t.Helper()
+	ctx := context.Background()
+
+	infer := false
+	_, err := mem0.AddContext(ctx, []types.Message{{Role: "user", Content: "Alice likes green tea"}}, types.AddOptions{
+		UserID:   "alice",
+		Infer:    &infer,
+		Metadata: map[string]any{"source": "chat", "timestamp": time.Now().UnixNano()},
+	})
+	require.NoError(t, err)
+
+	memories, err := mem0.SearchContext(ctx, "green tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}})
+	require.NoError(t, err)
+	return memories
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "session.yaml")
+	opts := cassette.Options{Mode: cassette.ModeAuto, IgnoreFields: []string{"metadata.timestamp"}}
+
+	srv := mem0test.NewServer(mem0test.WithAPIKey(liveKey))
+	rec, err := cassette.New(path, opts)
+	require.NoError(t, err)
+	require.True(t, rec.Recording())
+	assert.Equal(t, "mem_1", rec.Value("memory_id", "mem_1"))
+
+	recorded := session(t, newClient(t, srv.URL, rec))
+	require.Len(t, recorded, 1)
+	require.NoError(t, rec.Stop())
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), liveKey)
+	assert.NotContains(t, string(data), mem0test.UserEmail)
+	assert.Contains(t, string(data), cassette.RedactedEmail)
+
+	// 服务已经关闭, 回放不访问网络, 不同的时间戳仍然匹配
+	rec, err = cassette.New(path, opts)
+	require.NoError(t, err)
+	require.False(t, rec.Recording())
+	assert.Equal(t, "mem_1", rec.Value("memory_id", ""))
+
+	mem0 := newClient(t, srv.URL, rec)
+	replayed := session(t, mem0)
+	assert.Equal(t, recorded, replayed)
+
+	_, err = mem0.GetContext(context.Background(), "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction")
+	require.NoError(t, rec.Stop())
+}
+
+func TestReplayMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	srv := mem0test.NewServer(mem0test.WithAPIKey(liveKey))
+	defer srv.Close()
+
+	rec, err := cassette.New(path, cassette.Options{Mode: cassette.ModeRecord})
+	require.NoError(t, err)
+	session(t, newClient(t, srv.URL, rec))
+	require.NoError(t, rec.Stop())
+
+	// 没有忽略时间戳时请求体不匹配
+	rec, err = cassette.New(path, cassette.Options{Mode: cassette.ModeReplay})
+	require.NoError(t, err)
+	mem0 := newClient(t, srv.URL, rec)
+	_, err = mem0.AddContext(context.Background(), []types.Message{{Role: "user", Content: "Alice likes green tea"}}, types.AddOptions{
+		UserID:   "alice",
+		Metadata: map[string]any{"source": "chat", "timestamp": 1},
+	})
+	require.Error(t, err)
+
+	// 同一个请求被重复发送时依次回放, 默认用完后返回错误
+	_, err = mem0.Ping(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "have been replayed")
+
+	// AllowRepeat 时用完后重复最后一个
+	rec, err = cassette.New(path, cassette.Options{Mode: cassette.ModeReplay, AllowRepeat: true})
+	require.NoError(t, err)
+	mem0 = newClient(t, srv.URL, rec)
+	for i := 0; i < 3; i++ {
+		_, err := mem0.Ping(context.Background())
+		require.NoError(t, err)
+	}
+
+	_, err = cassette.New(filepath.Join(t.TempDir(), "missing.yaml"), cassette.Options{Mode: cassette.ModeReplay})
+	require.Error(t, err)
+}
+
+func TestParseMode(t *testing.T) {
+	for s, want := range map[string]cassette.Mode{
+		"":       cassette.ModeAuto,
+		"auto":   cassette.ModeAuto,
+		"Record": cassette.ModeRecord,
+		"replay": cassette.ModeReplay,
+	} {
+		mode, err := cassette.ParseMode(s)
+		require.NoError(t, err)
+		assert.Equal(t, want, mode, s)
+	}
+
+	_, err := cassette.ParseMode("live")
+	require.Error(t, err)
+}
+
+func TestReplayStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.yaml")
+	c := &cassette.Cassette{Version: cassette.Version, Interactions: []*cassette.Interaction{{
+		Request:  cassette.Request{Method: http.MethodGet, URL: "https://api.mem0.ai/v1/ping/"},
+		Response: cassette.Response{Status: http.StatusOK, Body: "{}"},
+	}}}
+	require.NoError(t, c.Save(path))
+
+	rec, err := cassette.New(path, cassette.Options{Mode: cassette.ModeReplay})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "https://api.mem0.ai/v1/ping/", nil)
+	require.NoError(t, err)
+	resp, err := rec.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "200 OK", resp.Status)
+}
@@ -0,0 +1,83 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Matcher 判断请求 req 是否与录制的请求 recorded 匹配, 两者都已去除敏感内容
+type Matcher func(req, recorded Request) bool
+
+// DefaultMatcher 比较方法, 路径, 查询参数和请求体, 忽略主机和请求头
+// 两个请求体都是 JSON 时按值比较并忽略 ignoreFields 中的字段, 否则按字节比较
+func DefaultMatcher(ignoreFields ...string) Matcher {
+	paths := make([][]string, len(ignoreFields))
+	for i, field := range ignoreFields {
+		paths[i] = strings.Split(field, ".")
+	}
+
+	return func(req, recorded Request) bool {
+		if req.Method != recorded.Method {
+			return false
+		}
+		if !sameURL(req.URL, recorded.URL) {
+			return false
+		}
+		return sameBody(req.Body, recorded.Body, paths)
+	}
+}
+
+// sameURL 比较路径和查询参数, 查询参数的顺序不影响结果
+func sameURL(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return a == b
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Path == ub.Path && reflect.DeepEqual(ua.Query(), ub.Query())
+}
+
+func sameBody(a, b string, ignore [][]string) bool {
+	if a == b {
+		return true
+	}
+
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	for _, path := range ignore {
+		removePath(va, path)
+		removePath(vb, path)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// removePath 删除 JSON 值中 path 指向的字段, 数组中的每个元素都会被处理
+func removePath(v any, path []string) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			removePath(item, path)
+		}
+	case map[string]any:
+		if len(path) == 0 {
+			return
+		}
+		for key, child := range v {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			if len(path) == 1 {
+				delete(v, key)
+				continue
+			}
+			removePath(child, path[1:])
+		}
+	}
+}
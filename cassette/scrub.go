@@ -0,0 +1,85 @@
+package cassette
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Redacted 替换录制内容中的密钥
+	Redacted = "REDACTED"
+	// RedactedEmail 替换录制内容中的邮箱
+	RedactedEmail = "user@example.com"
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	apiKeyPattern = regexp.MustCompile(`\bm0-[A-Za-z0-9_-]{8,}`)
+)
+
+// sensitiveHeaders 的值在录制时被整体替换
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// scrubber 去除录制内容中的 API key 和邮箱
+type scrubber struct {
+	secrets []string
+}
+
+func newScrubber(secrets []string) *scrubber {
+	s := &scrubber{}
+	for _, secret := range secrets {
+		if secret != "" {
+			s.secrets = append(s.secrets, secret)
+		}
+	}
+	return s
+}
+
+// scrub 替换字符串中的密钥, Mem0 API key 和邮箱
+func (s *scrubber) scrub(v string) string {
+	for _, secret := range s.secrets {
+		v = strings.ReplaceAll(v, secret, Redacted)
+	}
+	v = apiKeyPattern.ReplaceAllString(v, Redacted)
+	return emailPattern.ReplaceAllString(v, RedactedEmail)
+}
+
+// headers 返回去除敏感内容后的请求头副本
+// Content-Length 不被保存, 因为替换后长度会改变, 回放时按实际内容设置
+func (s *scrubber) headers(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for key, values := range h {
+		scrubbed := make([]string, len(values))
+		for i, value := range values {
+			scrubbed[i] = s.scrub(value)
+		}
+		out[key] = scrubbed
+	}
+	for _, key := range sensitiveHeaders {
+		if _, ok := out[key]; ok {
+			out.Set(key, Redacted)
+		}
+	}
+	out.Del("Content-Length")
+	return out
+}
+
+// request 将请求转换为去除敏感内容后的录制格式, 回放时也用它得到匹配用的请求
+func (s *scrubber) request(req *http.Request, body []byte) Request {
+	return Request{
+		Method:  req.Method,
+		URL:     s.scrub(req.URL.String()),
+		Headers: s.headers(req.Header),
+		Body:    s.scrub(string(body)),
+	}
+}
+
+// response 将响应转换为去除敏感内容后的录制格式
+func (s *scrubber) response(resp *http.Response, body []byte) Response {
+	return Response{
+		Status:  resp.StatusCode,
+		Headers: s.headers(resp.Header),
+		Body:    s.scrub(string(body)),
+	}
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// DefaultBatchSize 是 Mem0 平台单个批量请求允许的最大条目数
+const DefaultBatchSize = 1000
+
+// BatchOptions 定义批量操作的分块和并发策略
+type BatchOptions struct {
+	// BatchSize 是每个请求包含的条目数, 默认为 DefaultBatchSize
+	BatchSize int
+	// Concurrency 是同时发送的请求数, 默认为 4
+	Concurrency int
+}
+
+// withDefaults 返回填充了默认值的选项
+func (o BatchOptions) withDefaults() BatchOptions {
+	if o.BatchSize <= 0 || o.BatchSize > DefaultBatchSize {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	return o
+}
+
+// BatchItemResult 是批量操作中单个条目的结果
+type BatchItemResult struct {
+	MemoryID string
+	// Attempts 是发送该条目所在请求的次数, 大于 1 表示经过重试, 未发送时为 0
+	Attempts int
+	// Err 不为 nil 时表示该条目所在的请求失败或条目本身无效
+	Err error
+}
+
+// BatchReport 是批量操作的结果, Items 与输入顺序一致
+type BatchReport struct {
+	Items []BatchItemResult
+}
+
+// Succeeded 返回成功的内存 ID
+func (r *BatchReport) Succeeded() []string {
+	var ids []string
+	for _, item := range r.Items {
+		if item.Err == nil {
+			ids = append(ids, item.MemoryID)
+		}
+	}
+	return ids
+}
+
+// Failed 返回失败的条目
+func (r *BatchReport) Failed() []BatchItemResult {
+	var failed []BatchItemResult
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Retried 返回经过重试的内存 ID, 包括最终成功和失败的条目
+func (r *BatchReport) Retried() []string {
+	var ids []string
+	for _, item := range r.Items {
+		if item.Attempts > 1 {
+			ids = append(ids, item.MemoryID)
+		}
+	}
+	return ids
+}
+
+// Err 在有条目失败时返回包装了第一个失败原因的错误, 否则返回 nil
+func (r *BatchReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.Wrapf(failed[0].Err, "%d of %d batch items failed", len(failed), len(r.Items))
+}
+
+// BatchUpdateWithOptions 按 opts 分块并发更新内存, 返回每个条目的结果
+// 某个分块失败不会影响其他分块, 返回的错误与 BatchReport.Err 相同
+func (c *MemoryClient) BatchUpdateWithOptions(ctx context.Context, memories []types.MemoryUpdateBody, opts BatchOptions) (*BatchReport, error) {
+	ids := make([]string, len(memories))
+	for i, memory := range memories {
+		ids[i] = memory.MemoryID
+	}
+
+	report := c.runBatch(ctx, ids, opts, func(ctx context.Context, start, end int) (int, error) {
+		return c.callWithAttempts(ctx, "BatchUpdate", "PUT", "/v1/memories/batch/", memories[start:end])
+	})
+	return report, report.Err()
+}
+
+// BatchDeleteWithOptions 按 opts 分块并发删除内存, 返回每个条目的结果
+// 某个分块失败不会影响其他分块, 返回的错误与 BatchReport.Err 相同
+func (c *MemoryClient) BatchDeleteWithOptions(ctx context.Context, memoryIDs []string, opts BatchOptions) (*BatchReport, error) {
+	report := c.runBatch(ctx, memoryIDs, opts, func(ctx context.Context, start, end int) (int, error) {
+		return c.callWithAttempts(ctx, "BatchDelete", "DELETE", "/v1/memories/batch/", memoryIDs[start:end])
+	})
+	return report, report.Err()
+}
+
+// runBatch 将 ids 分块后并发调用 send, 并把每个分块的结果记录到对应的条目上
+// 内存 ID 为空的条目不会被发送
+func (c *MemoryClient) runBatch(ctx context.Context, ids []string, opts BatchOptions, send func(ctx context.Context, start, end int) (int, error)) *BatchReport {
+	opts = opts.withDefaults()
+
+	report := &BatchReport{Items: make([]BatchItemResult, len(ids))}
+	for i, id := range ids {
+		report.Items[i].MemoryID = id
+		if id == "" && !c.skipValidation {
+			report.Items[i].Err = errors.Wrap(ErrValidation, "memory_id is required")
+		}
+	}
+
+	// 无效的条目会打断分块, 保证每个请求中只有有效的条目
+	var chunks [][2]int
+	for start := 0; start < len(ids); {
+		if report.Items[start].Err != nil {
+			start++
+			continue
+		}
+		end := start
+		for end < len(ids) && end-start < opts.BatchSize && report.Items[end].Err == nil {
+			end++
+		}
+		chunks = append(chunks, [2]int{start, end})
+		start = end
+	}
+
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for _, chunk := range chunks {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			attempts, err := 0, ctx.Err()
+			if err == nil {
+				attempts, err = send(ctx, start, end)
+			}
+			if err != nil {
+				err = errors.Wrapf(err, "batch items %d-%d", start, end-1)
+			}
+			for i := start; i < end; i++ {
+				report.Items[i].Attempts = attempts
+				report.Items[i].Err = err
+			}
+		}(chunk[0], chunk[1])
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchDeleteChunks(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		sizes    []int
+		inflight int32
+		maxSeen  int32
+		attempts = map[string]int{}
+	)
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/v1/memories/batch/", r.URL.Path)
+
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+				break
+			}
+		}
+
+		var ids []string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&ids))
+
+		mu.Lock()
+		sizes = append(sizes, len(ids))
+		attempts[ids[0]]++
+		first := attempts[ids[0]]
+		mu.Unlock()
+
+		switch ids[0] {
+		case "m-10":
+			// 第二个分块首次失败, 重试后成功
+			if first == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "m-20":
+			// 第三个分块始终失败
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+		Retry:    testRetryPolicy(),
+	})
+	require.NoError(t, err)
+
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("m-%d", i)
+	}
+
+	report, err := client.BatchDeleteWithOptions(context.Background(), ids, BatchOptions{BatchSize: 10, Concurrency: 2})
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "5 of 25 batch items failed")
+
+	require.Len(t, report.Items, 25)
+	assert.Len(t, report.Succeeded(), 20)
+	assert.Equal(t, ids[10:20], report.Retried())
+
+	failed := report.Failed()
+	require.Len(t, failed, 5)
+	assert.Equal(t, "m-20", failed[0].MemoryID)
+	assert.Equal(t, 1, failed[0].Attempts)
+	assert.ErrorIs(t, failed[0].Err, ErrValidation)
+
+	assert.ElementsMatch(t, []int{10, 10, 10, 5}, sizes)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxSeen), int32(2))
+}
+
+func TestBatchUpdateSkipsInvalidItems(t *testing.T) {
+	var bodies [][]types.MemoryUpdateBody
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+
+		var body []types.MemoryUpdateBody
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+
+	report, err := client.BatchUpdateWithOptions(context.Background(), []types.MemoryUpdateBody{
+		{MemoryID: "a", Text: "A"},
+		{Text: "missing id"},
+		{MemoryID: "b", Text: "B"},
+	}, BatchOptions{Concurrency: 1})
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, []string{"a", "b"}, report.Succeeded())
+	require.Len(t, report.Failed(), 1)
+	assert.Equal(t, 0, report.Failed()[0].Attempts)
+
+	assert.ElementsMatch(t, [][]types.MemoryUpdateBody{
+		{{MemoryID: "a", Text: "A"}},
+		{{MemoryID: "b", Text: "B"}},
+	}, bodies)
+
+	// 兼容的 BatchUpdate 只返回错误
+	assert.NoError(t, client.BatchUpdate([]types.MemoryUpdateBody{{MemoryID: "c", Text: "C"}}))
+}
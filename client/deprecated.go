@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// 以下方法保留拆分选项之前的签名, 使旧代码无需修改即可编译,
+// 新代码请使用对应的 *Context 方法和新的选项类型
+
+// AddAsync adds a new memory asynchronously
+// `messages` can be a string, []string, types.Message, or []types.Message
+// Returns an event whose status can be used to track the outcome of the memory addition
+//
+// Deprecated: 使用 AddAsyncContext 和 types.AddOptions.
+func (c *MemoryClient) AddAsync(messages interface{}, options types.MemoryOptions) ([]types.MemoryAddAEvent, error) {
+	return c.AddAsyncContext(context.Background(), messages, options.ToAddOptions())
+}
+
+// Add adds a new memory synchronously
+// `messages` can be a string, []string, types.Message, or []types.Message
+// Returns the created memories
+//
+// Deprecated: 使用 AddContext 和 types.AddOptions.
+func (c *MemoryClient) Add(messages interface{}, options types.MemoryOptions) ([]types.Memory, error) {
+	return c.AddContext(context.Background(), messages, options.ToAddOptions())
+}
+
+// GetAll 获取所有内存
+//
+// Deprecated: 使用 GetAllContext 和 types.ListOptions.
+func (c *MemoryClient) GetAll(options *types.SearchOptions) ([]types.Memory, error) {
+	if options == nil {
+		return c.GetAllContext(context.Background(), nil)
+	}
+	return c.GetAllContext(context.Background(), options.ToListOptions())
+}
+
+// Search 搜索内存, options 的所有字段都会原样发送
+//
+// Deprecated: 使用 SearchContext 和 types.QueryOptions.
+func (c *MemoryClient) Search(query string, options *types.SearchOptions) ([]types.Memory, error) {
+	var opts types.SearchOptions
+	if options != nil {
+		opts = *options
+	}
+
+	queryOptions := opts.ToQueryOptions()
+	if err := c.validate(*queryOptions); err != nil {
+		return nil, err
+	}
+
+	return c.search(context.Background(), query, opts, queryOptions.Version)
+}
+
+// DeleteAll 删除所有内存
+//
+// Deprecated: 使用 DeleteAllContext 和 types.DeleteAllOptions.
+func (c *MemoryClient) DeleteAll(options types.MemoryOptions) error {
+	return c.DeleteAllContext(context.Background(), options.ToDeleteAllOptions())
+}
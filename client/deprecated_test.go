@@ -0,0 +1,59 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+func TestDeprecatedMethods(t *testing.T) {
+	bodies := make(map[string]map[string]any)
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies[r.Method+" "+r.URL.Path] = body
+		if r.Method == http.MethodDelete {
+			assert.Equal(t, "alice", r.URL.Query().Get("user_id"))
+			return
+		}
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{APIKey: "test-key", Host: server.URL})
+	require.NoError(t, err)
+
+	_, err = client.Add("test memory", types.MemoryOptions{UserID: "alice", Infer: true, APIVersion: types.V2})
+	require.NoError(t, err)
+	assert.Equal(t, true, bodies["POST /v1/memories/"]["infer"])
+	assert.Equal(t, "v2", bodies["POST /v1/memories/"]["version"])
+
+	_, err = client.Search("tea", &types.SearchOptions{
+		MemoryOptions: types.MemoryOptions{
+			Filters:   map[string]any{"user_id": "alice"},
+			StartDate: "2024-01-01",
+			EndDate:   "2024-12-31",
+		},
+		TopK: 3,
+	})
+	require.NoError(t, err)
+	search := bodies["POST /v2/memories/search/"]
+	assert.Equal(t, float64(3), search["top_k"])
+	assert.Equal(t, "2024-01-01", search["start_date"])
+	assert.Equal(t, "2024-12-31", search["end_date"])
+	assert.Equal(t, true, search["filter_memories"])
+
+	_, err = client.Search("tea", &types.SearchOptions{TopK: -1})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = client.GetAll(&types.SearchOptions{MemoryOptions: types.MemoryOptions{PageSize: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, float64(10), bodies["POST /v2/memories/"]["page_size"])
+
+	require.NoError(t, client.DeleteAll(types.MemoryOptions{UserID: "alice"}))
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// 可以使用 errors.Is 判断的错误类型
+var (
+	// ErrNotFound 表示请求的资源不存在 (404)
+	ErrNotFound = errors.New("resource not found")
+	// ErrUnauthorized 表示 API key 无效或没有权限 (401, 403)
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited 表示请求被限流 (429)
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation 表示请求参数无效, 可能由客户端检查或服务端返回 (400, 422)
+	// 客户端检查返回的 *types.ValidationError 列出了所有无效的字段
+	ErrValidation = types.ErrValidation
+	// ErrServer 表示服务端错误 (5xx)
+	ErrServer = errors.New("server error")
+
+	// ErrDuplicateWebhook 表示同一项目下已经存在相同 url 的 webhook
+	ErrDuplicateWebhook = errors.New("a webhook with the same project and url already exists")
+)
+
+// APIError 定义 API 错误
+//
+// 可以使用 errors.As 获取请求和响应的详细信息, 或使用 errors.Is 与
+// ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrValidation 和 ErrServer 比较
+type APIError struct {
+	// Message 是可读的错误信息, 优先使用服务端返回的 detail
+	Message string
+	// StatusCode 是响应的 HTTP 状态码
+	StatusCode int
+	// Method 和 Path 是失败请求的 HTTP 方法和路径
+	Method string
+	Path   string
+	// Detail 是服务端错误体中的 detail, error 或 message 字段
+	Detail string
+	// FieldErrors 是服务端错误体中按字段分组的错误信息
+	FieldErrors map[string][]string
+	// RequestID 是响应头中的请求 ID, 便于排查问题
+	RequestID string
+	// Body 是原始响应体
+	Body string
+	// Err 可选, 是更具体的错误, 例如 ErrDuplicateWebhook
+	Err error
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return e.Message
+	}
+
+	msg := fmt.Sprintf("%s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id: %s)", e.RequestID)
+	}
+	return msg
+}
+
+// Is 根据状态码匹配对应的哨兵错误
+func (e *APIError) Is(target error) bool {
+	return target != nil && target == statusError(e.StatusCode)
+}
+
+// Unwrap 返回更具体的错误
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// statusError 返回状态码对应的哨兵错误
+func statusError(code int) error {
+	switch {
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return ErrUnauthorized
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code == http.StatusBadRequest, code == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case code >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// requestIDHeaders 是可能携带请求 ID 的响应头
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Amzn-Requestid", "Cf-Ray"}
+
+// newAPIError 根据失败的响应创建 APIError
+func newAPIError(method, path string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+		Body:       string(body),
+	}
+
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+
+	apiErr.Detail, apiErr.FieldErrors = parseErrorBody(body)
+
+	switch {
+	case apiErr.Detail != "":
+		apiErr.Message = apiErr.Detail
+	case len(apiErr.FieldErrors) > 0:
+		apiErr.Message = formatFieldErrors(apiErr.FieldErrors)
+	case len(body) > 0:
+		apiErr.Message = strings.TrimSpace(string(body))
+	default:
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
+
+// parseErrorBody 解析服务端返回的错误体
+// 支持 {"detail": "..."}, {"error": "..."}, {"message": "..."} 以及
+// {"field": ["..."]} 形式的字段错误
+func parseErrorBody(body []byte) (string, map[string][]string) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return "", nil
+	}
+
+	var detail string
+	fieldErrors := make(map[string][]string)
+	for key, value := range raw {
+		var str string
+		var list []string
+		switch {
+		case json.Unmarshal(value, &str) == nil:
+			list = []string{str}
+		case json.Unmarshal(value, &list) == nil:
+		default:
+			continue
+		}
+
+		switch key {
+		case "detail", "error", "message":
+			if detail == "" {
+				detail = strings.Join(list, "; ")
+			}
+		default:
+			fieldErrors[key] = list
+		}
+	}
+
+	if len(fieldErrors) == 0 {
+		fieldErrors = nil
+	}
+	return detail, fieldErrors
+}
+
+// formatFieldErrors 将字段错误格式化为稳定顺序的字符串
+func formatFieldErrors(fieldErrors map[string][]string) string {
+	keys := make([]string, 0, len(fieldErrors))
+	for key := range fieldErrors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %s", key, strings.Join(fieldErrors[key], ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
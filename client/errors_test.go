@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorNotFound(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail": "Memory not found"}`))
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrValidation)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Equal(t, "/v1/memories/missing/", apiErr.Path)
+	assert.Equal(t, "Memory not found", apiErr.Detail)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+	assert.Equal(t, "GET /v1/memories/missing/ failed with status 404: Memory not found (request id: req-123)", apiErr.Error())
+}
+
+func TestAPIErrorFieldErrors(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"user_id": ["This field may not be blank."], "text": "Required."}`))
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Update("test-id", "")
+	assert.ErrorIs(t, err, ErrValidation)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, map[string][]string{
+		"user_id": {"This field may not be blank."},
+		"text":    {"Required."},
+	}, apiErr.FieldErrors)
+	assert.Equal(t, "text: Required.; user_id: This field may not be blank.", apiErr.Message)
+}
+
+func TestAPIErrorStatusSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusUnprocessableEntity, ErrValidation},
+		{http.StatusBadGateway, ErrServer},
+	}
+
+	for _, tt := range tests {
+		err := error(&APIError{StatusCode: tt.status})
+		assert.ErrorIs(t, err, tt.want, "status %d", tt.status)
+	}
+}
+
+func TestDuplicateWebhook(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"non_field_errors": ["The fields project, url must make a unique set."]}`))
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.CreateWebhook("test-project", types.WebhookPayload{
+		Name: "test-webhook",
+		URL:  "https://example.com",
+	})
+	assert.ErrorIs(t, err, ErrDuplicateWebhook)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestClientSideValidationError(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.CreateWebhook("", types.WebhookPayload{})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = client.AddContext(context.Background(), 42, types.AddOptions{})
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestValidationBeforeRequest(t *testing.T) {
+	var calls int
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.SearchContext(context.Background(), "query", &types.QueryOptions{
+		Threshold: 2,
+		Filters:   map[string]any{"user_id": "alice", "agent_id": "bot"},
+	})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	var verr *types.ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Errors, 2)
+
+	assert.ErrorIs(t, client.Feedback(types.FeedbackPayload{MemoryID: "m", Feedback: "GREAT"}), ErrValidation)
+	assert.ErrorIs(t, client.DeleteAllContext(context.Background(), types.DeleteAllOptions{}), ErrValidation)
+	assert.Equal(t, 0, calls)
+
+	// 关闭校验后原样发送
+	client, err = NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	}, WithValidation(false))
+	assert.NoError(t, err)
+
+	_, err = client.SearchContext(context.Background(), "query", &types.QueryOptions{Threshold: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
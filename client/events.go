@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// ErrEventFailed 表示异步事件以 FAILED 状态结束
+var ErrEventFailed = errors.New("event failed")
+
+// WaitOptions 定义等待异步事件完成时的轮询策略
+type WaitOptions struct {
+	// PollInterval 是首次轮询前的等待时间, 默认为 1s
+	PollInterval time.Duration
+	// MaxPollInterval 是轮询间隔的上限, 默认为 10s
+	MaxPollInterval time.Duration
+	// Multiplier 是每次轮询后间隔的增长倍数, 默认为 1.5, 设置为 1 时使用固定间隔
+	Multiplier float64
+	// Timeout 是等待的总时长, 0 表示只受 ctx 控制. WaitForEvents 中是所有事件共享的总时长, 包括排队等待并发名额的时间
+	Timeout time.Duration
+	// NotFoundGrace 是连续查询不到事件时继续轮询的时长, 默认为 30s
+	// 事件刚创建时可能还无法查询到, 超过这个时长后返回匹配 ErrNotFound 的错误
+	NotFoundGrace time.Duration
+	// Concurrency 是 WaitForEvents 同时等待的事件数, 默认为 8
+	Concurrency int
+}
+
+// withDefaults 返回填充了默认值的选项
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.MaxPollInterval <= 0 {
+		o.MaxPollInterval = 10 * time.Second
+	}
+	if o.MaxPollInterval < o.PollInterval {
+		o.MaxPollInterval = o.PollInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = 1.5
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 8
+	}
+	if o.NotFoundGrace <= 0 {
+		o.NotFoundGrace = 30 * time.Second
+	}
+	return o
+}
+
+// EventOutcome 是等待一个异步事件的结果
+type EventOutcome struct {
+	EventID string
+	// Event 是最后一次获取到的事件, 等待失败时可能为 nil
+	Event *types.Event
+	// Memories 是从事件 Results 中解码出的内存
+	Memories []types.Memory
+	// Err 不为 nil 时表示事件失败 (ErrEventFailed) 或等待失败
+	Err error
+}
+
+// EventOutcomes 是多个事件的等待结果
+type EventOutcomes []EventOutcome
+
+// Memories 返回所有成功事件中的内存
+func (o EventOutcomes) Memories() []types.Memory {
+	var memories []types.Memory
+	for _, outcome := range o {
+		if outcome.Err == nil {
+			memories = append(memories, outcome.Memories...)
+		}
+	}
+	return memories
+}
+
+// Err 返回第一个失败事件的错误
+func (o EventOutcomes) Err() error {
+	for _, outcome := range o {
+		if outcome.Err != nil {
+			return outcome.Err
+		}
+	}
+	return nil
+}
+
+// WaitForEvent 轮询 GetEvent 直到事件进入 SUCCEEDED 或 FAILED 状态
+// 事件失败时返回的错误匹配 ErrEventFailed, 此时 outcome 中仍然包含最终的事件
+func (c *MemoryClient) WaitForEvent(ctx context.Context, eventID string, opts WaitOptions) (*EventOutcome, error) {
+	opts = opts.withDefaults()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	outcome := &EventOutcome{EventID: eventID}
+	interval := opts.PollInterval
+	// notFoundSince 是连续查询不到事件的开始时间
+	var notFoundSince time.Time
+	for {
+		if err := sleepContext(ctx, interval); err != nil {
+			outcome.Err = errors.Wrapf(err, "waiting for event %s", eventID)
+			return outcome, outcome.Err
+		}
+
+		event, err := c.GetEventContext(ctx, eventID)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			// 事件刚创建时可能还无法查询到, 但事件 ID 错误或过期时不会再出现
+			if notFoundSince.IsZero() {
+				notFoundSince = time.Now()
+			} else if time.Since(notFoundSince) >= opts.NotFoundGrace {
+				outcome.Err = errors.Wrapf(err, "waiting for event %s", eventID)
+				return outcome, outcome.Err
+			}
+		case err != nil:
+			outcome.Err = errors.Wrapf(err, "waiting for event %s", eventID)
+			return outcome, outcome.Err
+		default:
+			notFoundSince = time.Time{}
+			outcome.Event = event
+			switch event.Status {
+			case types.EventStatusSUCCEEDED:
+				outcome.Memories, outcome.Err = event.MemoryResults()
+				return outcome, outcome.Err
+			case types.EventStatusFAILED:
+				outcome.Err = errors.Wrapf(ErrEventFailed, "event %s", eventID)
+				return outcome, outcome.Err
+			}
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+	}
+}
+
+// WaitForEvents 并发等待多个事件, 返回的结果与 eventIDs 顺序一致
+// 返回的错误是第一个失败事件的错误, 其余事件的结果仍然有效
+func (c *MemoryClient) WaitForEvents(ctx context.Context, eventIDs []string, opts WaitOptions) (EventOutcomes, error) {
+	opts = opts.withDefaults()
+	// 所有事件共享一个从调用开始计算的超时, 包括排队等待并发名额的时间
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		opts.Timeout = 0
+	}
+
+	outcomes := make(EventOutcomes, len(eventIDs))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, eventID := range eventIDs {
+		wg.Add(1)
+		go func(i int, eventID string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			outcome, _ := c.WaitForEvent(ctx, eventID, opts)
+			outcomes[i] = *outcome
+		}(i, eventID)
+	}
+	wg.Wait()
+
+	return outcomes, outcomes.Err()
+}
+
+// AddAndWait 异步添加内存并等待所有生成的事件完成
+// 可以使用 EventOutcomes.Memories 获取添加的内存
+func (c *MemoryClient) AddAndWait(ctx context.Context, messages interface{}, options types.AddOptions, opts WaitOptions) (EventOutcomes, error) {
+	events, err := c.AddAsyncContext(ctx, messages, options)
+	if err != nil {
+		return nil, err
+	}
+
+	eventIDs := make([]string, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.EventID
+	}
+
+	return c.WaitForEvents(ctx, eventIDs, opts)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWaitOptions 返回轮询间隔很短的等待选项
+func testWaitOptions() WaitOptions {
+	return WaitOptions{
+		PollInterval:    time.Millisecond,
+		MaxPollInterval: 5 * time.Millisecond,
+	}
+}
+
+// eventServer 模拟异步事件: 每个事件在被查询 polls 次后进入 final 状态
+func eventServer(t *testing.T, polls int, final map[string]types.EventStatus) *MemoryClient {
+	var mu sync.Mutex
+	seen := make(map[string]int)
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/memories/" {
+			var events []types.MemoryAddAEvent
+			for id := range final {
+				events = append(events, types.MemoryAddAEvent{EventID: id, Status: types.EventStatusPENDING})
+			}
+			json.NewEncoder(w).Encode(events)
+			return
+		}
+
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/event/"), "/")
+		mu.Lock()
+		seen[id]++
+		n := seen[id]
+		mu.Unlock()
+
+		event := types.Event{ID: id, EventType: types.EventTypeMemoryAdd, Status: types.EventStatusRUNNING}
+		if n >= polls {
+			event.Status = final[id]
+			if event.Status == types.EventStatusSUCCEEDED {
+				event.Results = []any{
+					map[string]any{"id": "mem-" + id, "event": "ADD", "data": map[string]any{"memory": "memory " + id}},
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(event)
+	})
+	t.Cleanup(server.Close)
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestWaitForEvent(t *testing.T) {
+	client := eventServer(t, 3, map[string]types.EventStatus{"ev-1": types.EventStatusSUCCEEDED})
+
+	outcome, err := client.WaitForEvent(context.Background(), "ev-1", testWaitOptions())
+	require.NoError(t, err)
+	assert.Equal(t, types.EventStatusSUCCEEDED, outcome.Event.Status)
+	require.Len(t, outcome.Memories, 1)
+	assert.Equal(t, "mem-ev-1", outcome.Memories[0].ID)
+	assert.Equal(t, "memory ev-1", outcome.Memories[0].Data.Memory)
+}
+
+func TestWaitForEventFailed(t *testing.T) {
+	client := eventServer(t, 1, map[string]types.EventStatus{"ev-1": types.EventStatusFAILED})
+
+	outcome, err := client.WaitForEvent(context.Background(), "ev-1", testWaitOptions())
+	assert.ErrorIs(t, err, ErrEventFailed)
+	assert.Equal(t, types.EventStatusFAILED, outcome.Event.Status)
+}
+
+func TestWaitForEventTimeout(t *testing.T) {
+	client := eventServer(t, 1000, map[string]types.EventStatus{"ev-1": types.EventStatusSUCCEEDED})
+
+	opts := testWaitOptions()
+	opts.Timeout = 20 * time.Millisecond
+	outcome, err := client.WaitForEvent(context.Background(), "ev-1", opts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, types.EventStatusRUNNING, outcome.Event.Status)
+}
+
+func TestAddAndWait(t *testing.T) {
+	client := eventServer(t, 2, map[string]types.EventStatus{
+		"ev-1": types.EventStatusSUCCEEDED,
+		"ev-2": types.EventStatusSUCCEEDED,
+		"ev-3": types.EventStatusFAILED,
+	})
+
+	outcomes, err := client.AddAndWait(context.Background(), "test memory", types.AddOptions{UserID: "test-user"}, testWaitOptions())
+	assert.ErrorIs(t, err, ErrEventFailed)
+	assert.Len(t, outcomes, 3)
+	assert.Len(t, outcomes.Memories(), 2)
+}
+
+func TestWaitForEventNotFound(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	t.Cleanup(server.Close)
+	client, err := NewMemoryClient(ClientOptions{APIKey: "test-key", Host: server.URL, PingMode: PingNever})
+	require.NoError(t, err)
+
+	// 没有 Timeout 时连续查询不到事件超过 NotFoundGrace 后停止等待
+	opts := testWaitOptions()
+	opts.NotFoundGrace = 20 * time.Millisecond
+	_, err = client.WaitForEvent(context.Background(), "missing", opts)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestWaitForEventsSharedTimeout(t *testing.T) {
+	client := eventServer(t, 1000, map[string]types.EventStatus{
+		"ev-1": types.EventStatusSUCCEEDED,
+		"ev-2": types.EventStatusSUCCEEDED,
+		"ev-3": types.EventStatusSUCCEEDED,
+	})
+
+	// 超时是所有事件共享的总时长, 不会因为排队等待并发名额而延长
+	opts := testWaitOptions()
+	opts.Timeout = 50 * time.Millisecond
+	opts.Concurrency = 1
+	start := time.Now()
+	outcomes, err := client.WaitForEvents(context.Background(), []string{"ev-1", "ev-2", "ev-3"}, opts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, outcomes, 3)
+	assert.Less(t, time.Since(start), 120*time.Millisecond)
+}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// ExportRecord 是导出文件中的一行, 内存的字段与 types.Memory 的 JSON 格式相同
+type ExportRecord struct {
+	types.Memory
+	// History 只在 ExportOptions.IncludeHistory 时导出
+	History []types.MemoryHistory `json:"history,omitempty"`
+}
+
+// ExportOptions 定义导出的范围和格式
+type ExportOptions struct {
+	// Scopes 是要导出的范围, 依次使用 GetAll 分页遍历, 为空时导出 GetAll 默认返回的所有内存
+	// 多个范围包含同一内存时会重复导出
+	Scopes []types.ListOptions
+	// PageSize 是每页的内存数, 默认为 DefaultIterPageSize, 会覆盖 Scopes 中的 PageSize
+	PageSize int
+	// IncludeHistory 为每个内存调用 History 并导出
+	IncludeHistory bool
+	// Gzip 使用 gzip 压缩输出, 每页是一个独立的 gzip member, gzip.Reader 可以直接读取
+	Gzip bool
+}
+
+// ExportStats 是导出的结果
+type ExportStats struct {
+	// Records 是文件中的记录数, 包括恢复前已经导出的记录
+	Records int
+	// Pages 是本次运行获取的页数
+	Pages int
+	// Resumed 表示从检查点恢复了中断的导出
+	Resumed bool
+}
+
+// exportCheckpoint 记录已经完整写入的页, 以及下一页的位置
+type exportCheckpoint struct {
+	// Fingerprint 是导出选项的摘要, 选项改变后不能恢复
+	Fingerprint string `json:"fingerprint"`
+	Scope       int    `json:"scope"`
+	Page        int    `json:"page"`
+	// Offset 是已经完整写入的页在文件中的结束位置, 之后的内容在恢复时被截断
+	Offset  int64 `json:"offset"`
+	Records int   `json:"records"`
+}
+
+// Export 将 opts 中的范围以 JSONL 格式写入 w, 不记录检查点
+func (c *MemoryClient) Export(ctx context.Context, w io.Writer, opts ExportOptions) (ExportStats, error) {
+	opts = opts.withDefaults()
+
+	var stats ExportStats
+	err := c.exportPages(ctx, opts, exportCheckpoint{Page: 1}, func(records []ExportRecord, _ exportCheckpoint) error {
+		stats.Pages++
+		stats.Records += len(records)
+		return writeExportPage(w, records, opts.Gzip)
+	})
+	return stats, err
+}
+
+// ExportFile 将 opts 中的范围以 JSONL 格式写入 path, 每写完一页在 path + ".checkpoint" 中记录进度
+// 如果检查点存在, 截断最后一次记录之后写入的内容并从下一页继续, 导出完成后删除检查点
+// 导出期间新增或删除的内存可能导致分页偏移, 使部分内存被遗漏或重复导出
+func (c *MemoryClient) ExportFile(ctx context.Context, path string, opts ExportOptions) (ExportStats, error) {
+	opts = opts.withDefaults()
+	fingerprint, err := opts.fingerprint()
+	if err != nil {
+		return ExportStats{}, err
+	}
+
+	checkpointPath := path + ".checkpoint"
+	start := exportCheckpoint{Fingerprint: fingerprint, Page: 1}
+	var stats ExportStats
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if data, err := os.ReadFile(checkpointPath); err == nil {
+		var saved exportCheckpoint
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return stats, errors.Wrap(err, "failed to read export checkpoint")
+		}
+		if saved.Fingerprint != fingerprint {
+			return stats, errors.Errorf("export checkpoint %s was written with different options, remove it to start over", checkpointPath)
+		}
+		start = saved
+		stats.Resumed = true
+		flags = os.O_WRONLY
+	} else if !os.IsNotExist(err) {
+		return stats, errors.Wrap(err, "failed to read export checkpoint")
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return stats, errors.Wrap(err, "failed to open export file")
+	}
+	defer file.Close()
+
+	if stats.Resumed {
+		if err := file.Truncate(start.Offset); err != nil {
+			return stats, errors.Wrap(err, "failed to truncate export file")
+		}
+		if _, err := file.Seek(start.Offset, io.SeekStart); err != nil {
+			return stats, errors.Wrap(err, "failed to seek export file")
+		}
+	}
+
+	stats.Records = start.Records
+	err = c.exportPages(ctx, opts, start, func(records []ExportRecord, next exportCheckpoint) error {
+		if err := writeExportPage(file, records, opts.Gzip); err != nil {
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			return errors.Wrap(err, "failed to sync export file")
+		}
+		pos, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return errors.Wrap(err, "failed to seek export file")
+		}
+
+		stats.Pages++
+		stats.Records += len(records)
+		next.Fingerprint = fingerprint
+		next.Offset = pos
+		next.Records = stats.Records
+		return writeExportCheckpoint(checkpointPath, next)
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	if err := file.Close(); err != nil {
+		return stats, errors.Wrap(err, "failed to close export file")
+	}
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		return stats, errors.Wrap(err, "failed to remove export checkpoint")
+	}
+	return stats, nil
+}
+
+// withDefaults 填充默认值
+func (o ExportOptions) withDefaults() ExportOptions {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultIterPageSize
+	}
+	if len(o.Scopes) == 0 {
+		o.Scopes = []types.ListOptions{{}}
+	}
+	return o
+}
+
+// fingerprint 返回影响导出内容的选项的摘要
+func (o ExportOptions) fingerprint() (string, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode export options")
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// exportPages 从 start 开始遍历所有范围的每一页, 并将页的记录和下一页的位置传给 page
+func (c *MemoryClient) exportPages(ctx context.Context, opts ExportOptions, start exportCheckpoint, page func([]ExportRecord, exportCheckpoint) error) error {
+	for scope := start.Scope; scope < len(opts.Scopes); scope++ {
+		pageNum := 1
+		if scope == start.Scope && start.Page > 0 {
+			pageNum = start.Page
+		}
+
+		for {
+			list := opts.Scopes[scope]
+			list.Page = pageNum
+			list.PageSize = opts.PageSize
+
+			memories, err := c.GetAllContext(ctx, &list)
+			if err != nil {
+				return errors.Wrapf(err, "failed to export scope %d page %d", scope, pageNum)
+			}
+
+			records := make([]ExportRecord, len(memories))
+			for i, memory := range memories {
+				records[i].Memory = memory
+				if opts.IncludeHistory {
+					if records[i].History, err = c.HistoryContext(ctx, memory.ID); err != nil {
+						return errors.Wrapf(err, "failed to export history of memory %s", memory.ID)
+					}
+				}
+			}
+
+			// 服务端可能限制每页的条目数, 只有空页表示这个范围已经导出完
+			more := len(memories) > 0
+			next := exportCheckpoint{Scope: scope, Page: pageNum + 1}
+			if !more {
+				next = exportCheckpoint{Scope: scope + 1, Page: 1}
+			}
+			if err := page(records, next); err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+			pageNum++
+		}
+	}
+	return nil
+}
+
+// writeExportPage 将一页记录写入 w, compress 时写入一个完整的 gzip member
+func writeExportPage(w io.Writer, records []ExportRecord, compress bool) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	buf := bufio.NewWriter(w)
+	var out io.Writer = buf
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(buf)
+		out = zw
+	}
+
+	encoder := json.NewEncoder(out)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return errors.Wrap(err, "failed to write export record")
+		}
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return errors.Wrap(err, "failed to write export record")
+		}
+	}
+	return errors.Wrap(buf.Flush(), "failed to write export record")
+}
+
+// writeExportCheckpoint 先写入临时文件再重命名, 避免崩溃时留下不完整的检查点
+func writeExportCheckpoint(path string, checkpoint exportCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return errors.Wrap(err, "failed to write export checkpoint")
+	}
+	return errors.Wrap(os.Rename(tmp, path), "failed to write export checkpoint")
+}
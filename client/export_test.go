@@ -0,0 +1,179 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExportClient 返回一个客户端, 服务端为每个 user_id 提供 counts 中数量的内存
+// failPage 大于 0 时, alice 的该页第一次请求返回 400
+func newExportClient(t *testing.T, counts map[string]int, failPage int) *MemoryClient {
+	var failed int32
+	client := newPagingClient(t, func(*httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/v1/memories/") && strings.HasSuffix(r.URL.Path, "/history/") {
+				id := strings.Split(r.URL.Path, "/")[3]
+				json.NewEncoder(w).Encode([]types.MemoryHistory{{ID: "h-" + id, MemoryID: id}})
+				return
+			}
+
+			var req struct {
+				Page     int            `json:"page"`
+				PageSize int            `json:"page_size"`
+				Filters  map[string]any `json:"filters"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			user, _ := req.Filters["user_id"].(string)
+			if user == "alice" && req.Page == failPage && atomic.CompareAndSwapInt32(&failed, 0, 1) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			memories := []types.Memory{}
+			for i := (req.Page - 1) * req.PageSize; i < req.Page*req.PageSize && i < counts[user]; i++ {
+				memories = append(memories, types.Memory{ID: fmt.Sprintf("%s-%d", user, i), Memory: "text", UserID: user})
+			}
+			json.NewEncoder(w).Encode(memories)
+		}
+	})
+	return client
+}
+
+func exportScopes(users ...string) []types.ListOptions {
+	scopes := make([]types.ListOptions, len(users))
+	for i, user := range users {
+		scopes[i].Filters = map[string]any{"user_id": user}
+	}
+	return scopes
+}
+
+func readExport(t *testing.T, r io.Reader, compressed bool) []ExportRecord {
+	t.Helper()
+	if compressed {
+		zr, err := gzip.NewReader(r)
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	}
+
+	var records []ExportRecord
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var record ExportRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func recordIDs(records []ExportRecord) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+func TestExport(t *testing.T) {
+	client := newExportClient(t, map[string]int{"alice": 3, "bob": 2}, 0)
+
+	var buf bytes.Buffer
+	stats, err := client.Export(context.Background(), &buf, ExportOptions{
+		Scopes:         exportScopes("alice", "bob"),
+		PageSize:       2,
+		IncludeHistory: true,
+	})
+	require.NoError(t, err)
+	// 每个范围以一个空页结束
+	assert.Equal(t, ExportStats{Records: 5, Pages: 5}, stats)
+
+	records := readExport(t, &buf, false)
+	assert.Equal(t, []string{"alice-0", "alice-1", "alice-2", "bob-0", "bob-1"}, recordIDs(records))
+	assert.Equal(t, "alice", records[0].UserID)
+	require.Len(t, records[0].History, 1)
+	assert.Equal(t, "h-alice-0", records[0].History[0].ID)
+}
+
+func TestExportFileResume(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("gzip=%v", compressed), func(t *testing.T) {
+			client := newExportClient(t, map[string]int{"alice": 5, "bob": 1}, 2)
+			path := filepath.Join(t.TempDir(), "export.jsonl")
+			opts := ExportOptions{Scopes: exportScopes("alice", "bob"), PageSize: 2, Gzip: compressed}
+
+			// 第二页失败, 检查点记录第一页
+			stats, err := client.ExportFile(context.Background(), path, opts)
+			require.Error(t, err)
+			assert.Equal(t, 2, stats.Records)
+			assert.FileExists(t, path+".checkpoint")
+
+			// 选项不同时拒绝恢复
+			other := opts
+			other.IncludeHistory = true
+			_, err = client.ExportFile(context.Background(), path, other)
+			assert.ErrorContains(t, err, "different options")
+
+			stats, err = client.ExportFile(context.Background(), path, opts)
+			require.NoError(t, err)
+			assert.True(t, stats.Resumed)
+			assert.Equal(t, 6, stats.Records)
+			assert.Equal(t, 5, stats.Pages)
+			assert.NoFileExists(t, path+".checkpoint")
+
+			f, err := os.Open(path)
+			require.NoError(t, err)
+			defer f.Close()
+			assert.Equal(t,
+				[]string{"alice-0", "alice-1", "alice-2", "alice-3", "alice-4", "bob-0"},
+				recordIDs(readExport(t, f, compressed)))
+		})
+	}
+}
+
+func TestExportFileTruncatesPartialPage(t *testing.T) {
+	client := newExportClient(t, map[string]int{"alice": 3}, 0)
+	path := filepath.Join(t.TempDir(), "export.jsonl")
+	opts := ExportOptions{Scopes: exportScopes("alice"), PageSize: 2}
+	fingerprint, err := opts.withDefaults().fingerprint()
+	require.NoError(t, err)
+
+	// 模拟第一页写完后崩溃, 第二页只写入了一部分
+	first, err := json.Marshal(ExportRecord{Memory: types.Memory{ID: "alice-0"}})
+	require.NoError(t, err)
+	second, err := json.Marshal(ExportRecord{Memory: types.Memory{ID: "alice-1"}})
+	require.NoError(t, err)
+	content := string(first) + "\n" + string(second) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content+`{"id":"alice-2","mem`), 0o644))
+	require.NoError(t, writeExportCheckpoint(path+".checkpoint", exportCheckpoint{
+		Fingerprint: fingerprint,
+		Page:        2,
+		Offset:      int64(len(content)),
+		Records:     2,
+	}))
+
+	stats, err := client.ExportFile(context.Background(), path, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Records)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"alice-0", "alice-1", "alice-2"}, recordIDs(readExport(t, f, false)))
+}
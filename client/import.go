@@ -0,0 +1,473 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// ImportFormat 是导入文件的格式
+type ImportFormat int
+
+const (
+	// ImportJSONL 每行一个 JSON 对象, 也可以读取 Export 导出的文件
+	ImportJSONL ImportFormat = iota
+	// ImportCSV 第一行是列名: text, user_id, agent_id, app_id, run_id, metadata (JSON 对象), timestamp, hash
+	// 以 metadata. 开头的列写入 metadata 中对应的键
+	ImportCSV
+)
+
+// ImportRecord 是导入的一条记录
+type ImportRecord struct {
+	// Line 是记录在输入中的行号, CSV 从数据的第一行 (即第 2 行) 开始
+	Line int `json:"-"`
+
+	Text     string         `json:"text"`
+	UserID   string         `json:"user_id,omitempty"`
+	AgentID  string         `json:"agent_id,omitempty"`
+	AppID    string         `json:"app_id,omitempty"`
+	RunID    string         `json:"run_id,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// Timestamp 是 Unix 时间戳 (秒), 为 0 时使用服务端当前时间
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Hash 是内存文本的摘要, 为空时使用文本的 MD5, 与 Mem0 计算 types.Memory.Hash 的方式相同
+	Hash string `json:"hash,omitempty"`
+}
+
+// UnmarshalJSON 同时支持导入格式和 Export 的格式 (memory, session_id, created_at)
+func (r *ImportRecord) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Text      string          `json:"text"`
+		Memory    string          `json:"memory"`
+		UserID    string          `json:"user_id"`
+		AgentID   string          `json:"agent_id"`
+		AppID     string          `json:"app_id"`
+		RunID     string          `json:"run_id"`
+		SessionID string          `json:"session_id"`
+		Metadata  map[string]any  `json:"metadata"`
+		Timestamp json.RawMessage `json:"timestamp"`
+		CreatedAt time.Time       `json:"created_at"`
+		Hash      string          `json:"hash"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = ImportRecord{
+		Line:     r.Line,
+		Text:     firstNonEmpty(raw.Text, raw.Memory),
+		UserID:   raw.UserID,
+		AgentID:  raw.AgentID,
+		AppID:    raw.AppID,
+		RunID:    firstNonEmpty(raw.RunID, raw.SessionID),
+		Metadata: raw.Metadata,
+		Hash:     raw.Hash,
+	}
+
+	if len(raw.Timestamp) > 0 && string(raw.Timestamp) != "null" {
+		timestamp, err := parseImportTimestamp(strings.Trim(string(raw.Timestamp), `"`))
+		if err != nil {
+			return err
+		}
+		r.Timestamp = timestamp
+	} else if !raw.CreatedAt.IsZero() {
+		r.Timestamp = raw.CreatedAt.Unix()
+	}
+	return nil
+}
+
+// hash 返回记录的摘要
+func (r ImportRecord) hash() string {
+	if r.Hash != "" {
+		return r.Hash
+	}
+	sum := md5.Sum([]byte(r.Text))
+	return hex.EncodeToString(sum[:])
+}
+
+// scope 返回用于查重的实体, 按 user_id, agent_id, app_id, run_id 的顺序取第一个
+func (r ImportRecord) scope() (string, string) {
+	for _, entity := range [][2]string{
+		{"user_id", r.UserID},
+		{"agent_id", r.AgentID},
+		{"app_id", r.AppID},
+		{"run_id", r.RunID},
+	} {
+		if entity[1] != "" {
+			return entity[0], entity[1]
+		}
+	}
+	return "", ""
+}
+
+// ReadImportRecords 读取 r 中的所有记录, 任何一行无法解析时返回带行号的错误
+func ReadImportRecords(r io.Reader, format ImportFormat) ([]ImportRecord, error) {
+	if format == ImportCSV {
+		return readImportCSV(r)
+	}
+
+	var records []ImportRecord
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(data))) > 0 {
+			record := ImportRecord{Line: line}
+			if jsonErr := json.Unmarshal(data, &record); jsonErr != nil {
+				return nil, errors.Wrapf(jsonErr, "line %d", line)
+			}
+			records = append(records, record)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+	}
+}
+
+// readImportCSV 读取带列名的 CSV
+func readImportCSV(r io.Reader) ([]ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CSV header")
+	}
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		switch header[i] {
+		case "text", "memory", "user_id", "agent_id", "app_id", "run_id", "metadata", "timestamp", "hash":
+		default:
+			if !strings.HasPrefix(header[i], "metadata.") {
+				return nil, errors.Errorf("unknown CSV column %q", header[i])
+			}
+		}
+	}
+
+	var records []ImportRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		record := ImportRecord{Line: line}
+		for i, value := range row {
+			if i >= len(header) || value == "" {
+				continue
+			}
+			switch column := header[i]; column {
+			case "text", "memory":
+				record.Text = value
+			case "user_id":
+				record.UserID = value
+			case "agent_id":
+				record.AgentID = value
+			case "app_id":
+				record.AppID = value
+			case "run_id":
+				record.RunID = value
+			case "hash":
+				record.Hash = value
+			case "timestamp":
+				if record.Timestamp, err = parseImportTimestamp(value); err != nil {
+					return nil, errors.Wrapf(err, "line %d", line)
+				}
+			case "metadata":
+				var metadata map[string]any
+				if err := json.Unmarshal([]byte(value), &metadata); err != nil {
+					return nil, errors.Wrapf(err, "line %d: invalid metadata", line)
+				}
+				for k, v := range metadata {
+					record.setMetadata(k, v)
+				}
+			default:
+				record.setMetadata(strings.TrimPrefix(column, "metadata."), value)
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+func (r *ImportRecord) setMetadata(key string, value any) {
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]any)
+	}
+	r.Metadata[key] = value
+}
+
+// parseImportTimestamp 解析 Unix 时间戳 (秒) 或 RFC3339 时间
+func parseImportTimestamp(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(seconds), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, errors.Errorf("invalid timestamp %q, want Unix seconds or RFC3339", value)
+	}
+	return t.Unix(), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// IDMap 将旧的实体 ID 映射为新的 ID, 键 "*" 匹配其他所有非空 ID
+type IDMap map[string]string
+
+// apply 返回 id 映射后的值, 没有匹配的规则时原样返回
+func (m IDMap) apply(id string) string {
+	if id == "" {
+		return id
+	}
+	if mapped, ok := m[id]; ok {
+		return mapped
+	}
+	if mapped, ok := m["*"]; ok {
+		return mapped
+	}
+	return id
+}
+
+// EntityRemap 定义导入时实体 ID 的映射规则
+type EntityRemap struct {
+	Users  IDMap
+	Agents IDMap
+	Apps   IDMap
+	Runs   IDMap
+}
+
+// apply 映射记录的实体 ID
+func (m EntityRemap) apply(r *ImportRecord) {
+	r.UserID = m.Users.apply(r.UserID)
+	r.AgentID = m.Agents.apply(r.AgentID)
+	r.AppID = m.Apps.apply(r.AppID)
+	r.RunID = m.Runs.apply(r.RunID)
+}
+
+// ImportAction 是一条记录的导入结果
+type ImportAction int
+
+const (
+	// ImportCreated 表示记录已经添加
+	ImportCreated ImportAction = iota
+	// ImportWouldCreate 表示 DryRun 时记录将被添加
+	ImportWouldCreate
+	// ImportDuplicate 表示记录与输入中之前的记录或已有的内存重复, 没有添加
+	ImportDuplicate
+	// ImportSkipped 表示记录在检查点中已经完成, 没有再次添加
+	ImportSkipped
+	// ImportFailed 表示记录无效或者添加失败
+	ImportFailed
+)
+
+func (a ImportAction) String() string {
+	switch a {
+	case ImportCreated:
+		return "created"
+	case ImportWouldCreate:
+		return "would-create"
+	case ImportDuplicate:
+		return "duplicate"
+	case ImportSkipped:
+		return "skipped"
+	}
+	return "failed"
+}
+
+// ImportOptions 定义导入的格式, 映射规则和查重
+type ImportOptions struct {
+	Format ImportFormat
+	// SkipInference 设置 Infer=false, 原样保存文本而不是从中提取内存
+	SkipInference bool
+	// Remap 在查重和添加前映射实体 ID
+	Remap EntityRemap
+	// DedupeExisting 在导入前读取每个实体已有的内存, 跳过 Hash 相同的记录
+	// 输入中 Hash 相同且属于同一实体的记录总是只导入第一条
+	// 服务端提取内存时会改写文本, 因此只有 SkipInference 时按文本计算的 Hash 才能匹配已有的内存
+	DedupeExisting bool
+	// DryRun 只检查和报告, 不添加任何内存; 没有设置 DedupeExisting 时不发送任何请求
+	DryRun bool
+	// Ingest 定义并发, 限流和检查点, 检查点的键是记录的行号
+	Ingest IngestOptions
+	// OnResult 可选, 每条记录处理完后调用, 不会被并发调用
+	OnResult func(ImportResult)
+}
+
+// ImportResult 是一条记录的导入结果
+type ImportResult struct {
+	Record ImportRecord
+	Action ImportAction
+	// Memories 是添加的内存
+	Memories []types.Memory
+	Err      error
+}
+
+// ImportReport 汇总导入的结果
+type ImportReport struct {
+	Created     int
+	WouldCreate int
+	Duplicates  int
+	Skipped     int
+	Failed      int
+}
+
+// Import 读取 r 中的所有记录并使用 Add 添加, 输入无法解析时不添加任何记录并返回错误
+// 单条记录失败不会中止导入, 而是计入 ImportReport.Failed 并通过 OnResult 报告
+func (c *MemoryClient) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	records, err := ReadImportRecords(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{}
+	emit := func(result ImportResult) {
+		switch result.Action {
+		case ImportCreated:
+			report.Created++
+		case ImportWouldCreate:
+			report.WouldCreate++
+		case ImportDuplicate:
+			report.Duplicates++
+		case ImportSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+	}
+
+	existing := make(map[string]bool)
+	if opts.DedupeExisting {
+		if existing, err = c.existingHashes(ctx, records, opts.Remap); err != nil {
+			return report, err
+		}
+	}
+
+	var items []IngestItem
+	var pending []ImportRecord
+	for _, record := range records {
+		opts.Remap.apply(&record)
+
+		options := types.AddOptions{
+			UserID:    record.UserID,
+			AgentID:   record.AgentID,
+			AppID:     record.AppID,
+			RunID:     record.RunID,
+			Metadata:  record.Metadata,
+			Timestamp: record.Timestamp,
+		}
+		if opts.SkipInference {
+			infer := false
+			options.Infer = &infer
+		}
+
+		if record.Text == "" {
+			emit(ImportResult{Record: record, Action: ImportFailed, Err: errors.Wrap(ErrValidation, "text is required")})
+			continue
+		}
+		if err := c.validate(options); err != nil {
+			emit(ImportResult{Record: record, Action: ImportFailed, Err: err})
+			continue
+		}
+
+		key := importHashKey(record)
+		if existing[key] {
+			emit(ImportResult{Record: record, Action: ImportDuplicate})
+			continue
+		}
+		existing[key] = true
+
+		if opts.DryRun {
+			emit(ImportResult{Record: record, Action: ImportWouldCreate})
+			continue
+		}
+
+		items = append(items, IngestItem{
+			Key:      "line-" + strconv.Itoa(record.Line),
+			Messages: record.Text,
+			Options:  options,
+		})
+		pending = append(pending, record)
+	}
+
+	for result := range c.IngestSlice(ctx, items, opts.Ingest) {
+		imported := ImportResult{Record: pending[result.Index], Memories: result.Memories, Err: result.Err}
+		switch {
+		case result.Err != nil:
+			imported.Action = ImportFailed
+		case result.Skipped:
+			imported.Action = ImportSkipped
+		default:
+			imported.Action = ImportCreated
+		}
+		emit(imported)
+	}
+	return report, ctx.Err()
+}
+
+// importHashKey 返回实体和 Hash 组成的查重键
+func importHashKey(record ImportRecord) string {
+	field, id := record.scope()
+	return field + "\x00" + id + "\x00" + record.hash()
+}
+
+// existingHashes 读取 records 涉及的每个实体已有内存的 Hash
+func (c *MemoryClient) existingHashes(ctx context.Context, records []ImportRecord, remap EntityRemap) (map[string]bool, error) {
+	scopes := make(map[[2]string]bool)
+	for _, record := range records {
+		remap.apply(&record)
+		if field, id := record.scope(); id != "" {
+			scopes[[2]string{field, id}] = true
+		}
+	}
+
+	hashes := make(map[string]bool)
+	for scope := range scopes {
+		it := c.IterMemories(ctx, &types.ListOptions{Filters: map[string]any{scope[0]: scope[1]}})
+		for it.Next() {
+			memory := it.Value()
+			hash := memory.Hash
+			if hash == "" {
+				hash = ImportRecord{Text: memory.Memory}.hash()
+			}
+			hashes[scope[0]+"\x00"+scope[1]+"\x00"+hash] = true
+		}
+		err := it.Err()
+		it.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list existing memories of %s %s", scope[0], scope[1])
+		}
+	}
+	return hashes, nil
+}
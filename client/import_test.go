@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadImportRecords(t *testing.T) {
+	t.Run("jsonl", func(t *testing.T) {
+		input := `{"text": "likes tea", "user_id": "alice", "metadata": {"source": "crm"}, "timestamp": 1700000000}
+
+{"memory": "exported", "session_id": "run-1", "created_at": "2024-01-02T03:04:05Z", "hash": "abc", "history": []}
+{"text": "rfc3339", "agent_id": "bot", "timestamp": "2024-01-02T03:04:05Z"}
+`
+		records, err := ReadImportRecords(strings.NewReader(input), ImportJSONL)
+		require.NoError(t, err)
+		assert.Equal(t, []ImportRecord{
+			{Line: 1, Text: "likes tea", UserID: "alice", Metadata: map[string]any{"source": "crm"}, Timestamp: 1700000000},
+			{Line: 3, Text: "exported", RunID: "run-1", Timestamp: 1704164645, Hash: "abc"},
+			{Line: 4, Text: "rfc3339", AgentID: "bot", Timestamp: 1704164645},
+		}, records)
+
+		_, err = ReadImportRecords(strings.NewReader("{\"text\": \"ok\"}\n{bad\n"), ImportJSONL)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		input := "text,user_id,metadata,metadata.team,timestamp\n" +
+			"\"likes tea, not coffee\",alice,\"{\"\"source\"\": \"\"crm\"\"}\",core,1700000000\n" +
+			"plain,bob,,,\n"
+		records, err := ReadImportRecords(strings.NewReader(input), ImportCSV)
+		require.NoError(t, err)
+		assert.Equal(t, []ImportRecord{
+			{Line: 2, Text: "likes tea, not coffee", UserID: "alice", Metadata: map[string]any{"source": "crm", "team": "core"}, Timestamp: 1700000000},
+			{Line: 3, Text: "plain", UserID: "bob"},
+		}, records)
+
+		_, err = ReadImportRecords(strings.NewReader("text,owner\nx,y\n"), ImportCSV)
+		assert.ErrorContains(t, err, `unknown CSV column "owner"`)
+
+		_, err = ReadImportRecords(strings.NewReader("text,timestamp\nx,yesterday\n"), ImportCSV)
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
+
+func TestImport(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		added []map[string]any
+	)
+	existing := ImportRecord{Text: "already there"}.hash()
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/memories/":
+			var req struct {
+				Filters map[string]any `json:"filters"`
+				Page    int            `json:"page"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			memories := []types.Memory{}
+			if req.Filters["user_id"] == "new-alice" && req.Page == 1 {
+				memories = append(memories, types.Memory{ID: "m-old", Memory: "already there", Hash: existing})
+			}
+			json.NewEncoder(w).Encode(memories)
+		case "/v1/memories/":
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			added = append(added, body)
+			mu.Unlock()
+			json.NewEncoder(w).Encode([]types.Memory{{ID: "m-new"}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{APIKey: "test-key", Host: server.URL, PingMode: PingNever})
+	require.NoError(t, err)
+
+	input := strings.Join([]string{
+		`{"text": "likes tea", "user_id": "alice"}`,
+		`{"text": "likes tea", "user_id": "alice"}`,
+		`{"text": "likes tea", "user_id": "bob"}`,
+		`{"text": "already there", "user_id": "alice"}`,
+		`{"text": "", "user_id": "alice"}`,
+		`{"text": "no entity"}`,
+	}, "\n")
+
+	opts := ImportOptions{
+		SkipInference:  true,
+		Remap:          EntityRemap{Users: IDMap{"alice": "new-alice"}},
+		DedupeExisting: true,
+		DryRun:         true,
+	}
+	var actions []string
+	opts.OnResult = func(result ImportResult) {
+		actions = append(actions, result.Record.UserID+":"+result.Action.String())
+	}
+
+	report, err := client.Import(context.Background(), strings.NewReader(input), opts)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReport{WouldCreate: 2, Duplicates: 2, Failed: 2}, report)
+	assert.Equal(t, []string{
+		"new-alice:would-create",
+		"new-alice:duplicate",
+		"bob:would-create",
+		"new-alice:duplicate",
+		"new-alice:failed",
+		":failed",
+	}, actions)
+	assert.Empty(t, added, "dry run must not add memories")
+
+	opts.DryRun = false
+	actions = nil
+	report, err = client.Import(context.Background(), strings.NewReader(input), opts)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReport{Created: 2, Duplicates: 2, Failed: 2}, report)
+	require.Len(t, added, 2)
+	for _, body := range added {
+		assert.Equal(t, false, body["infer"])
+		assert.Contains(t, []any{"new-alice", "bob"}, body["user_id"])
+	}
+}
+
+func TestIDMap(t *testing.T) {
+	m := IDMap{"alice": "a2", "*": "everyone"}
+	assert.Equal(t, "a2", m.apply("alice"))
+	assert.Equal(t, "everyone", m.apply("bob"))
+	assert.Equal(t, "", m.apply(""))
+	assert.Equal(t, "bob", IDMap(nil).apply("bob"))
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// IngestMode 定义批量导入时如何添加每个条目
+type IngestMode int
+
+const (
+	// IngestSync 使用 Add 同步添加
+	IngestSync IngestMode = iota
+	// IngestAsync 使用 AddAsync 添加, 并等待生成的事件完成
+	IngestAsync
+)
+
+// IngestItem 是批量导入的一个条目
+type IngestItem struct {
+	// Key 唯一标识条目, 用于检查点; 为空时使用条目在输入中的序号
+	Key string
+	// Messages 与 Add 的参数相同, 可以是 string, []string, types.Message 或 []types.Message
+	Messages interface{}
+	Options  types.AddOptions
+}
+
+// IngestOptions 定义批量导入的并发, 限流和断点续传
+type IngestOptions struct {
+	// Workers 是同时添加的条目数, 默认为 4
+	Workers int
+	// RateLimit 是每秒最多发起的添加请求数, 0 表示不限制
+	RateLimit float64
+	// Mode 定义如何添加每个条目, 默认为 IngestSync
+	Mode IngestMode
+	// Wait 是 IngestAsync 模式下等待事件的轮询策略
+	Wait WaitOptions
+	// Checkpoint 可选, 记录成功的条目, 重新运行时跳过它们
+	Checkpoint Checkpoint
+}
+
+// IngestResult 是一个条目的导入结果
+type IngestResult struct {
+	// Index 是条目在输入中的序号
+	Index int
+	Key   string
+	// Memories 是添加的内存, IngestAsync 模式下来自成功的事件
+	Memories []types.Memory
+	// Skipped 表示条目在检查点中已经完成, 没有再次添加
+	Skipped bool
+	Err     error
+}
+
+// Ingest 使用 worker 池并发添加 items 中的条目, 结果在完成时依次发送到返回的 channel
+// 返回的 channel 在 items 关闭且所有条目处理完, 或 ctx 被取消后关闭, 调用方必须读完它
+// ctx 取消后不再从 items 中等待新的条目, 正在处理的条目和 items 中已经排队的条目仍然各有一个结果, 其 Err 包含 ctx.Err()
+// 结果的顺序与输入顺序不一定相同, 可以使用 IngestResult.Index 对应
+func (c *MemoryClient) Ingest(ctx context.Context, items <-chan IngestItem, opts IngestOptions) <-chan IngestResult {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+
+	type job struct {
+		index int
+		item  IngestItem
+	}
+
+	jobs := make(chan job)
+	results := make(chan IngestResult, opts.Workers)
+	var limiter *tokenBucket
+	if opts.RateLimit > 0 {
+		limiter = newTokenBucket(opts.RateLimit, 1)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		index := 0
+		cancelled := func(item IngestItem) {
+			results <- cancelledResult(ctx, index, item)
+			index++
+		}
+		for {
+			select {
+			case item, ok := <-items:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- job{index: index, item: item}:
+					index++
+				case <-ctx.Done():
+					cancelled(item)
+					drainIngestItems(items, cancelled)
+					return
+				}
+			case <-ctx.Done():
+				drainIngestItems(items, cancelled)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- c.ingestItem(ctx, j.index, j.item, opts, limiter)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// drainIngestItems 对 items 中已经排队的条目调用 fn, 不等待新的条目
+func drainIngestItems(items <-chan IngestItem, fn func(IngestItem)) {
+	for {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return
+			}
+			fn(item)
+		default:
+			return
+		}
+	}
+}
+
+// cancelledResult 返回 ctx 取消后没有处理的条目的结果
+func cancelledResult(ctx context.Context, index int, item IngestItem) IngestResult {
+	result := IngestResult{Index: index, Key: item.Key, Err: ctx.Err()}
+	if result.Key == "" {
+		result.Key = strconv.Itoa(index)
+	}
+	return result
+}
+
+// IngestSlice 与 Ingest 相同, 但从切片中读取条目, ctx 取消后所有未处理的条目都有结果
+func (c *MemoryClient) IngestSlice(ctx context.Context, items []IngestItem, opts IngestOptions) <-chan IngestResult {
+	ch := make(chan IngestItem, len(items))
+	for _, item := range items {
+		ch <- item
+	}
+	close(ch)
+	return c.Ingest(ctx, ch, opts)
+}
+
+// ingestItem 添加一个条目并在成功后写入检查点
+func (c *MemoryClient) ingestItem(ctx context.Context, index int, item IngestItem, opts IngestOptions, limiter *tokenBucket) IngestResult {
+	result := IngestResult{Index: index, Key: item.Key}
+	if result.Key == "" {
+		result.Key = strconv.Itoa(index)
+	}
+
+	if opts.Checkpoint != nil && opts.Checkpoint.Done(result.Key) {
+		result.Skipped = true
+		return result
+	}
+
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	if _, err := limiter.wait(ctx); err != nil {
+		result.Err = err
+		return result
+	}
+
+	switch opts.Mode {
+	case IngestAsync:
+		var outcomes EventOutcomes
+		outcomes, result.Err = c.AddAndWait(ctx, item.Messages, item.Options, opts.Wait)
+		result.Memories = outcomes.Memories()
+	default:
+		result.Memories, result.Err = c.AddContext(ctx, item.Messages, item.Options)
+	}
+
+	if result.Err != nil {
+		result.Err = errors.Wrapf(result.Err, "ingest item %s", result.Key)
+		return result
+	}
+
+	if opts.Checkpoint != nil {
+		if err := opts.Checkpoint.MarkDone(result.Key); err != nil {
+			result.Err = errors.Wrapf(err, "failed to checkpoint item %s", result.Key)
+		}
+	}
+	return result
+}
+
+// Checkpoint 记录已经成功导入的条目, 用于中断后继续导入
+// 实现需要支持并发调用
+type Checkpoint interface {
+	// Done 判断条目是否已经成功导入
+	Done(key string) bool
+	// MarkDone 记录条目已经成功导入
+	MarkDone(key string) error
+}
+
+// FileCheckpoint 将成功的条目逐行追加到文件中, 进程崩溃后已写入的记录仍然有效
+type FileCheckpoint struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[string]bool
+}
+
+var _ Checkpoint = (*FileCheckpoint)(nil)
+
+// OpenFileCheckpoint 打开或创建检查点文件, 并加载其中已经完成的条目
+func OpenFileCheckpoint(path string) (*FileCheckpoint, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open checkpoint")
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, "failed to read checkpoint")
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if last := len(lines) - 1; lines[last] != "" {
+		// 崩溃时可能只写入了半行, 丢弃它并补上换行, 避免与下一条记录连在一起
+		lines = lines[:last]
+		if _, err := file.WriteString("\n"); err != nil {
+			file.Close()
+			return nil, errors.Wrap(err, "failed to repair checkpoint")
+		}
+	}
+
+	done := make(map[string]bool)
+	for _, line := range lines {
+		// MarkDone 不允许 key 包含换行, 只需去掉行尾, 保留 key 中的空白
+		if key := strings.TrimSuffix(line, "\r"); key != "" {
+			done[key] = true
+		}
+	}
+
+	return &FileCheckpoint{file: file, done: done}, nil
+}
+
+// Done 判断条目是否已经成功导入
+func (c *FileCheckpoint) Done(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done[key]
+}
+
+// MarkDone 将条目追加到检查点文件并同步到磁盘
+func (c *FileCheckpoint) MarkDone(key string) error {
+	if strings.ContainsAny(key, "\r\n") {
+		return errors.Errorf("checkpoint key %q must not contain newlines", key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.done[key] {
+		return nil
+	}
+	if _, err := c.file.WriteString(key + "\n"); err != nil {
+		return err
+	}
+	if err := c.file.Sync(); err != nil {
+		return err
+	}
+	c.done[key] = true
+	return nil
+}
+
+// Len 返回已经完成的条目数
+func (c *FileCheckpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.done)
+}
+
+// Close 关闭检查点文件
+func (c *FileCheckpoint) Close() error {
+	return c.file.Close()
+}
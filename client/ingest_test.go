@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ingestItems 创建 n 个条目, 内容为 "item-<i>"
+func ingestItems(n int) []IngestItem {
+	items := make([]IngestItem, n)
+	for i := range items {
+		items[i] = IngestItem{
+			Key:      fmt.Sprintf("k-%d", i),
+			Messages: fmt.Sprintf("item-%d", i),
+			Options:  types.AddOptions{UserID: "test-user"},
+		}
+	}
+	return items
+}
+
+func TestIngestResumesFromCheckpoint(t *testing.T) {
+	var calls int32
+	failing := int32(1)
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var payload struct {
+			Messages []types.Message `json:"messages"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		content := payload.Messages[0].Content
+		if content == "item-7" && atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode([]types.Memory{{ID: "mem-" + content}})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	run := func() (succeeded, skipped, failed []string) {
+		checkpoint, err := OpenFileCheckpoint(path)
+		require.NoError(t, err)
+		defer checkpoint.Close()
+
+		for result := range client.IngestSlice(context.Background(), ingestItems(10), IngestOptions{
+			Workers:    3,
+			Checkpoint: checkpoint,
+		}) {
+			switch {
+			case result.Err != nil:
+				failed = append(failed, result.Key)
+			case result.Skipped:
+				skipped = append(skipped, result.Key)
+			default:
+				require.Len(t, result.Memories, 1)
+				assert.Equal(t, fmt.Sprintf("mem-item-%d", result.Index), result.Memories[0].ID)
+				succeeded = append(succeeded, result.Key)
+			}
+		}
+		sort.Strings(succeeded)
+		sort.Strings(skipped)
+		return succeeded, skipped, failed
+	}
+
+	succeeded, skipped, failed := run()
+	assert.Len(t, succeeded, 9)
+	assert.Empty(t, skipped)
+	assert.Equal(t, []string{"k-7"}, failed)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&calls))
+
+	// 第二次运行只重新添加失败的条目
+	atomic.StoreInt32(&failing, 0)
+	succeeded, skipped, failed = run()
+	assert.Equal(t, []string{"k-7"}, succeeded)
+	assert.Len(t, skipped, 9)
+	assert.Empty(t, failed)
+	assert.Equal(t, int32(11), atomic.LoadInt32(&calls))
+}
+
+func TestIngestAsync(t *testing.T) {
+	client := eventServer(t, 2, map[string]types.EventStatus{"ev-1": types.EventStatusSUCCEEDED})
+
+	var results []IngestResult
+	for result := range client.IngestSlice(context.Background(), ingestItems(3), IngestOptions{
+		Mode: IngestAsync,
+		Wait: testWaitOptions(),
+	}) {
+		results = append(results, result)
+	}
+
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		require.Len(t, result.Memories, 1)
+		assert.Equal(t, "mem-ev-1", result.Memories[0].ID)
+	}
+}
+
+func TestIngestRateLimit(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	n := 0
+	for result := range client.IngestSlice(context.Background(), ingestItems(5), IngestOptions{Workers: 5, RateLimit: 100}) {
+		assert.NoError(t, result.Err)
+		n++
+	}
+	assert.Equal(t, 5, n)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestIngestCancel(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+
+	// 无限的输入在 ctx 取消后停止
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items := make(chan IngestItem)
+	go func() {
+		for {
+			select {
+			case items <- IngestItem{Messages: "item", Options: types.AddOptions{UserID: "test-user"}}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	n := 0
+	for range client.Ingest(ctx, items, IngestOptions{Workers: 2}) {
+		n++
+		if n == 5 {
+			cancel()
+		}
+	}
+	assert.GreaterOrEqual(t, n, 5)
+}
+
+func TestIngestSliceCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 第一个请求取消 ctx, 所有请求在测试结束前都不返回
+	release := make(chan struct{})
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+
+	items := make([]IngestItem, 20)
+	for i := range items {
+		items[i] = IngestItem{Messages: "item", Options: types.AddOptions{UserID: "test-user"}}
+	}
+
+	seen := make(map[int]bool)
+	for result := range client.IngestSlice(ctx, items, IngestOptions{Workers: 2}) {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+		assert.False(t, seen[result.Index])
+		seen[result.Index] = true
+	}
+	assert.Len(t, seen, len(items))
+}
+
+func TestFileCheckpointRepairsPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	require.NoError(t, os.WriteFile(path, []byte("a\nb\npart"), 0o644))
+
+	checkpoint, err := OpenFileCheckpoint(path)
+	require.NoError(t, err)
+	assert.True(t, checkpoint.Done("a"))
+	assert.True(t, checkpoint.Done("b"))
+	assert.False(t, checkpoint.Done("part"))
+	assert.Equal(t, 2, checkpoint.Len())
+
+	require.NoError(t, checkpoint.MarkDone("c"))
+	require.NoError(t, checkpoint.MarkDone(" d "))
+	assert.Error(t, checkpoint.MarkDone("bad\nkey"))
+	require.NoError(t, checkpoint.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb\npart\nc\n d \n", string(data))
+
+	// 重新打开后 key 中的空白保持不变
+	checkpoint, err = OpenFileCheckpoint(path)
+	require.NoError(t, err)
+	defer checkpoint.Close()
+	assert.True(t, checkpoint.Done(" d "))
+	assert.False(t, checkpoint.Done("d"))
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Request 描述一次 API 调用, 拦截器可以在调用 next 之前修改它
+type Request struct {
+	// Operation 是发起调用的客户端方法名, 例如 "Add", "Search" 或 "Ping"
+	Operation string
+	// Method 和 Path 是 HTTP 方法和 API 路径 (不包含 host 和 base path)
+	Method string
+	Path   string
+	// Header 是本次调用额外的请求头, Authorization 请求头不能被覆盖
+	Header http.Header
+	// Body 是 JSON 编码前的请求体, 可能为 nil
+	// 大多数写操作的请求体是 map[string]interface{}, 拦截器可以直接修改或替换它
+	Body interface{}
+
+	out interface{}
+}
+
+// Response 描述一次 API 调用的结果
+// 请求未能发出时 (例如网络错误) 只有 Attempts 有效
+type Response struct {
+	// StatusCode 和 Header 来自最后一次 HTTP 响应
+	StatusCode int
+	Header     http.Header
+	// Body 是原始响应体
+	Body []byte
+	// Result 是解码后的响应, 与客户端方法返回的值相同 (以指针形式), 失败或无响应体时为 nil
+	Result interface{}
+	// Attempts 是发送 HTTP 请求的次数, 包含重试
+	Attempts int
+}
+
+// Handler 执行一次 API 调用
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor 包装一次 API 调用, 可以在调用 next 前后观察或修改请求和响应
+// 即使 next 返回错误, 也应将它返回的 Response 原样返回
+type Interceptor func(ctx context.Context, req *Request, next Handler) (*Response, error)
+
+// WithInterceptors 添加拦截器, 先添加的拦截器位于外层, 最先看到请求, 最后看到响应
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *MemoryClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// chainInterceptors 将拦截器组合成一个 Handler
+func chainInterceptors(interceptors []Interceptor, final Handler) Handler {
+	handler := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req *Request) (*Response, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return handler
+}
+
+// LoggingInterceptor 使用 slog 记录每次调用的方法, 路径, 状态码, 耗时和重试次数
+// 成功的调用使用 Info 级别, 失败的调用使用 Error 级别; logger 为 nil 时使用 slog.Default()
+func LoggingInterceptor(logger *slog.Logger) Interceptor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+
+		attrs := []slog.Attr{
+			slog.String("operation", req.Operation),
+			slog.String("method", req.Method),
+			slog.String("path", req.Path),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if resp != nil {
+			attrs = append(attrs,
+				slog.Int("status", resp.StatusCode),
+				slog.Int("attempts", resp.Attempts),
+			)
+		}
+
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+			logger.LogAttrs(ctx, slog.LevelError, "mem0 request failed", attrs...)
+		} else {
+			logger.LogAttrs(ctx, slog.LevelInfo, "mem0 request", attrs...)
+		}
+
+		return resp, err
+	}
+}
+
+// HeaderInterceptor 为每次调用添加固定的请求头
+func HeaderInterceptor(headers http.Header) Interceptor {
+	return HeaderFuncInterceptor(func(context.Context) http.Header {
+		return headers
+	})
+}
+
+// HeaderFuncInterceptor 为每次调用添加由 ctx 计算出的请求头, 例如从 ctx 中读取租户 ID
+func HeaderFuncInterceptor(fn func(ctx context.Context) http.Header) Interceptor {
+	return func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		for key, values := range fn(ctx) {
+			req.Header.Del(key)
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptorChain(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, map[string]any{"tenant": "acme"}, payload["metadata"])
+		json.NewEncoder(w).Encode([]types.Memory{{ID: "test-id"}})
+	})
+	defer server.Close()
+
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+			order = append(order, name+" before")
+			resp, err := next(ctx, req)
+			order = append(order, name+" after")
+			return resp, err
+		}
+	}
+
+	// 在 Add 的请求体中注入租户信息
+	injectTenant := func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		if payload, ok := req.Body.(map[string]interface{}); ok && req.Operation == "Add" {
+			payload["metadata"] = map[string]any{"tenant": "acme"}
+		}
+		return next(ctx, req)
+	}
+
+	var result interface{}
+	capture := func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		resp, err := next(ctx, req)
+		if req.Operation == "Add" {
+			result = resp.Result
+			assert.Equal(t, 1, resp.Attempts)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+		return resp, err
+	}
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	}, WithInterceptors(record("outer"), record("inner")), WithInterceptors(injectTenant, capture))
+	assert.NoError(t, err)
+
+	memories, err := client.AddContext(context.Background(), "test memory", types.AddOptions{UserID: "test-user"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+	assert.Equal(t, &memories, result)
+}
+
+func TestInterceptorSeesErrors(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	var status int
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	}, WithInterceptors(func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		resp, err := next(ctx, req)
+		status = resp.StatusCode
+		assert.Nil(t, resp.Result)
+		return resp, err
+	}))
+	assert.NoError(t, err)
+
+	_, err = client.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	}, WithInterceptors(LoggingInterceptor(logger)))
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Delete("test-id"))
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "mem0 request", entry["msg"])
+	assert.Equal(t, "Delete", entry["operation"])
+	assert.Equal(t, "DELETE", entry["method"])
+	assert.Equal(t, "/v1/memories/test-id/", entry["path"])
+	assert.Equal(t, float64(http.StatusOK), entry["status"])
+	assert.NotContains(t, buf.String(), "test-key")
+}
+
+func TestHeaderInterceptor(t *testing.T) {
+	type tenantKey struct{}
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "static", r.Header.Get("X-Static"))
+		assert.Equal(t, "acme", r.Header.Get("X-Tenant"))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	}, WithInterceptors(
+		HeaderInterceptor(http.Header{"X-Static": []string{"static"}}),
+		HeaderFuncInterceptor(func(ctx context.Context) http.Header {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return http.Header{"X-Tenant": []string{tenant}}
+		}),
+	))
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	assert.NoError(t, client.DeleteContext(ctx, "test-id"))
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// DefaultIterPageSize 是 IterMemories 默认的每页条目数
+const DefaultIterPageSize = 100
+
+// Iterator 遍历分页接口返回的所有条目, 在后台预取下一页
+//
+//	it := mem0.IterMemories(ctx, opts)
+//	defer it.Close()
+//	for it.Next() {
+//		memory := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	pages  chan iterPage[T]
+	buf    []T
+	cur    T
+	err    error
+	done   bool
+}
+
+// iterPage 是后台获取的一页结果
+type iterPage[T any] struct {
+	items []T
+	err   error
+}
+
+// pageFetcher 获取下一页, more 为 false 时表示没有更多页面
+type pageFetcher[T any] func(ctx context.Context) (items []T, more bool, err error)
+
+// newIterator 创建 Iterator 并在后台开始获取第一页
+func newIterator[T any](ctx context.Context, fetch pageFetcher[T]) *Iterator[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &Iterator[T]{
+		ctx:    ctx,
+		cancel: cancel,
+		// 缓冲一页, 调用方处理当前页时预取下一页
+		pages: make(chan iterPage[T], 1),
+	}
+
+	go func() {
+		defer close(it.pages)
+		for {
+			items, more, err := fetch(ctx)
+			if err == nil && ctx.Err() != nil {
+				err = ctx.Err()
+			}
+
+			select {
+			case it.pages <- iterPage[T]{items: items, err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil || !more {
+				return
+			}
+		}
+	}()
+
+	return it
+}
+
+// Next 前进到下一个条目, 没有更多条目或发生错误时返回 false
+func (it *Iterator[T]) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done {
+			return false
+		}
+
+		page, ok := <-it.pages
+		if !ok {
+			// ctx 被取消时后台 goroutine 可能不发送错误直接退出
+			it.finish(it.ctx.Err())
+			return false
+		}
+		if page.err != nil {
+			it.finish(page.err)
+			return false
+		}
+		it.buf = page.items
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Value 返回当前条目
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err 返回遍历过程中发生的错误, ctx 取消时返回 ctx 的错误
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close 停止后台预取, 提前结束遍历时应调用
+func (it *Iterator[T]) Close() {
+	it.finish(nil)
+}
+
+// finish 结束遍历并记录第一个错误
+func (it *Iterator[T]) finish(err error) {
+	if it.err == nil {
+		it.err = err
+	}
+	it.done = true
+	it.buf = nil
+	it.cancel()
+}
+
+// IterMemories 使用 GetAll 遍历符合条件的所有内存, 自动翻页
+// options 中的 Page 是起始页 (默认为 1), PageSize 默认为 DefaultIterPageSize
+func (c *MemoryClient) IterMemories(ctx context.Context, options *types.ListOptions) *Iterator[types.Memory] {
+	var opts types.ListOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultIterPageSize
+	}
+
+	return newIterator(ctx, func(ctx context.Context) ([]types.Memory, bool, error) {
+		memories, err := c.GetAllContext(ctx, &opts)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to get page %d", opts.Page)
+		}
+
+		// 服务端可能限制每页的条目数, 短页不代表最后一页, 只有空页表示结束
+		opts.Page++
+		return memories, len(memories) > 0, nil
+	})
+}
+
+// IterEvents 使用 GetEvents 遍历所有事件, 自动跟随 next 游标
+func (c *MemoryClient) IterEvents(ctx context.Context) *Iterator[types.Event] {
+	cursor := ""
+	return newIterator(ctx, func(ctx context.Context) ([]types.Event, bool, error) {
+		events, err := c.GetEventsContext(ctx, cursor)
+		if err != nil {
+			return nil, false, err
+		}
+
+		cursor = events.Next
+		return events.Results, cursor != "", nil
+	})
+}
+
+// IterEntities 使用 Users 接口遍历所有实体 (用户, 智能体, 应用和运行), 自动跟随 next 游标
+func (c *MemoryClient) IterEntities(ctx context.Context) *Iterator[types.User] {
+	cursor := "/v1/users/"
+	return newIterator(ctx, func(ctx context.Context) ([]types.User, bool, error) {
+		users, err := c.usersPage(ctx, cursor)
+		if err != nil {
+			return nil, false, err
+		}
+
+		cursor, _ = users.Next.(string)
+		return users.Results, cursor != "", nil
+	})
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPagingClient(t *testing.T, handler func(server *httptest.Server) http.HandlerFunc) *MemoryClient {
+	var server *httptest.Server
+	server = newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		handler(server)(w, r)
+	})
+	t.Cleanup(server.Close)
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestIterMemories(t *testing.T) {
+	const total = 5
+	client := newPagingClient(t, func(*httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Page     int            `json:"page"`
+				PageSize int            `json:"page_size"`
+				Filters  map[string]any `json:"filters"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, 2, req.PageSize)
+			assert.Equal(t, "alice", req.Filters["user_id"])
+
+			var memories []types.Memory
+			for i := (req.Page - 1) * req.PageSize; i < req.Page*req.PageSize && i < total; i++ {
+				memories = append(memories, types.Memory{ID: fmt.Sprintf("mem-%d", i)})
+			}
+			json.NewEncoder(w).Encode(memories)
+		}
+	})
+
+	it := client.IterMemories(context.Background(), &types.ListOptions{
+		PageSize: 2,
+		Filters:  map[string]any{"user_id": "alice"},
+	})
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"mem-0", "mem-1", "mem-2", "mem-3", "mem-4"}, ids)
+}
+
+func TestIterMemoriesCappedPageSize(t *testing.T) {
+	const total, maxPageSize = 5, 2
+	client := newPagingClient(t, func(*httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Page int `json:"page"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			// 服务端每页最多返回 maxPageSize 条, 忽略请求的 page_size
+			memories := []types.Memory{}
+			for i := (req.Page - 1) * maxPageSize; i < req.Page*maxPageSize && i < total; i++ {
+				memories = append(memories, types.Memory{ID: fmt.Sprintf("mem-%d", i)})
+			}
+			json.NewEncoder(w).Encode(memories)
+		}
+	})
+
+	it := client.IterMemories(context.Background(), &types.ListOptions{PageSize: 10})
+	defer it.Close()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, total, count)
+}
+
+func TestIterEvents(t *testing.T) {
+	client := newPagingClient(t, func(server *httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/events/", r.URL.Path)
+			resp := types.GetEventsResponse{Count: 3}
+			switch r.URL.Query().Get("page") {
+			case "":
+				resp.Results = []types.Event{{ID: "ev-1"}, {ID: "ev-2"}}
+				resp.Next = server.URL + "/v1/events/?page=2"
+			case "2":
+				resp.Results = []types.Event{{ID: "ev-3"}}
+			}
+			json.NewEncoder(w).Encode(resp)
+		}
+	})
+
+	it := client.IterEvents(context.Background())
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"ev-1", "ev-2", "ev-3"}, ids)
+}
+
+func TestIterEntities(t *testing.T) {
+	client := newPagingClient(t, func(server *httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			resp := types.AllUsers{Count: 2}
+			if r.URL.Query().Get("page") == "" {
+				resp.Results = []types.User{{ID: "u-1", Type: "user"}}
+				resp.Next = server.URL + "/v1/users/?page=2"
+			} else {
+				resp.Results = []types.User{{ID: "a-1", Type: "agent"}}
+			}
+			json.NewEncoder(w).Encode(resp)
+		}
+	})
+
+	it := client.IterEntities(context.Background())
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"u-1", "a-1"}, ids)
+}
+
+func TestIteratorCancel(t *testing.T) {
+	client := newPagingClient(t, func(*httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// 永远返回满页
+			json.NewEncoder(w).Encode([]types.Memory{{ID: "a"}, {ID: "b"}})
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := client.IterMemories(ctx, &types.ListOptions{PageSize: 2})
+	defer it.Close()
+
+	count := 0
+	for it.Next() {
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.False(t, it.Next())
+}
+
+func TestIteratorError(t *testing.T) {
+	client := newPagingClient(t, func(*httptest.Server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	it := client.IterMemories(context.Background(), nil)
+	defer it.Close()
+
+	assert.False(t, it.Next())
+	assert.ErrorIs(t, it.Err(), ErrUnauthorized)
+}
@@ -2,11 +2,15 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -14,15 +18,6 @@ import (
 	"github.com/bytectlgo/mem0-go/types"
 )
 
-// APIError 定义 API 错误
-type APIError struct {
-	Message string
-}
-
-func (e *APIError) Error() string {
-	return e.Message
-}
-
 // ClientOptions 定义客户端选项
 type ClientOptions struct {
 	APIKey           string
@@ -31,7 +26,24 @@ type ClientOptions struct {
 	ProjectName      string
 	OrganizationID   string
 	ProjectID        string
-}
+	// Retry 可选, 定义失败请求的重试策略, 为 nil 时不重试
+	Retry *RetryPolicy
+	// PingMode 定义何时调用 /v1/ping/ 校验 API key 并获取组织和项目信息, 默认为 PingEager
+	PingMode PingMode
+}
+
+// PingMode 定义客户端何时调用 /v1/ping/
+type PingMode int
+
+const (
+	// PingEager 在 NewMemoryClient 中同步 ping, ping 失败时返回错误
+	PingEager PingMode = iota
+	// PingLazy 在第一次 API 调用前 ping, 之后填充组织 ID, 项目 ID 和遥测 ID
+	// ping 失败时该次调用返回错误, 下一次调用会重新尝试
+	PingLazy
+	// PingNever 从不自动 ping, 仅使用 ClientOptions 中的组织和项目 ID
+	PingNever
+)
 
 // MemoryClient 定义内存客户端
 type MemoryClient struct {
@@ -41,34 +53,67 @@ type MemoryClient struct {
 	projectID      string
 	client         *http.Client
 	telemetryID    string
+	retry          *RetryPolicy
+	basePath       string
+	userAgent      string
+	headers        http.Header
+	pingMode       PingMode
+	interceptors   []Interceptor
+	handler        Handler
+	skipValidation bool
+	limiter        *RateLimiter
+
+	// initMu 保护首次 ping 对 organizationID, projectID 和 telemetryID 的写入
+	initMu sync.Mutex
+	ready  uint32
 }
 
 // NewMemoryClient 创建新的内存客户端
-func NewMemoryClient(options ClientOptions) (*MemoryClient, error) {
+// opts 在 options 之后应用, 可用于替换 HTTP 客户端, 设置超时和额外的请求头等
+func NewMemoryClient(options ClientOptions, opts ...Option) (*MemoryClient, error) {
+	return NewMemoryClientContext(context.Background(), options, opts...)
+}
+
+// NewMemoryClientContext 与 NewMemoryClient 相同, 但使用 ctx 控制启动时的 ping 请求
+func NewMemoryClientContext(ctx context.Context, options ClientOptions, opts ...Option) (*MemoryClient, error) {
 	if options.APIKey == "" {
 		return nil, errors.New("API key is required")
 	}
 
 	if options.Host == "" {
-		options.Host = "https://api.mem0.ai"
+		options.Host = DefaultHost
 	}
 
 	client := &MemoryClient{
 		apiKey:         options.APIKey,
-		host:           options.Host,
+		host:           strings.TrimSuffix(options.Host, "/"),
 		organizationID: options.OrganizationID,
 		projectID:      options.ProjectID,
 		client: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		retry:     options.Retry,
+		userAgent: DefaultUserAgent,
+		headers:   make(http.Header),
+		pingMode:  options.PingMode,
+	}
+
+	for _, opt := range opts {
+		opt(client)
 	}
+	client.handler = chainInterceptors(client.interceptors, client.roundTrip)
 
 	if err := client.validateOrgProject(); err != nil {
 		return nil, err
 	}
 
-	if err := client.ping(); err != nil {
-		return nil, err
+	switch client.pingMode {
+	case PingEager:
+		if _, err := client.Ping(ctx); err != nil {
+			return nil, err
+		}
+	case PingNever:
+		client.ready = 1
 	}
 
 	return client, nil
@@ -83,51 +128,63 @@ func (c *MemoryClient) validateOrgProject() error {
 	return nil
 }
 
-// ping 检查 API 连接
-func (c *MemoryClient) ping() error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/ping/", c.host), nil)
-	if err != nil {
-		return err
-	}
+// Ping 检查 API 连接并返回 API key 所属的组织, 项目和用户邮箱
+// 客户端尚未初始化时 (PingLazy 模式下的首次调用前), 同时填充组织 ID, 项目 ID 和遥测 ID
+func (c *MemoryClient) Ping(ctx context.Context) (*types.PingResponse, error) {
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
 
-	req.Header.Set("Authorization", fmt.Sprintf("Token %s", c.apiKey))
+	return c.pingLocked(ctx)
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return err
+// ensureReady 在 PingLazy 模式下确保首次 ping 已经成功
+func (c *MemoryClient) ensureReady(ctx context.Context) error {
+	if atomic.LoadUint32(&c.ready) == 1 {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
 
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+	if atomic.LoadUint32(&c.ready) == 1 {
+		return nil
 	}
 
-	var data struct {
-		Status    string `json:"status"`
-		OrgID     string `json:"org_id"`
-		ProjectID string `json:"project_id"`
-		UserEmail string `json:"user_email"`
-	}
+	_, err := c.pingLocked(ctx)
+	return err
+}
 
-	if err := json.Unmarshal(body, &data); err != nil {
-		return err
+// pingLocked 调用 /v1/ping/, 调用方需持有 initMu
+func (c *MemoryClient) pingLocked(ctx context.Context) (*types.PingResponse, error) {
+	var data types.PingResponse
+	if err := c.call(ctx, "Ping", "GET", pingPath, nil, &data); err != nil {
+		return nil, err
 	}
 
 	if data.Status != "ok" {
-		return &APIError{Message: "API key is invalid"}
+		return nil, &APIError{Message: "API key is invalid", Err: ErrUnauthorized}
 	}
 
-	c.organizationID = data.OrgID
-	c.projectID = data.ProjectID
-	c.telemetryID = data.UserEmail
+	if atomic.LoadUint32(&c.ready) == 0 {
+		c.organizationID = data.OrgID
+		c.projectID = data.ProjectID
+		c.telemetryID = data.UserEmail
+		atomic.StoreUint32(&c.ready, 1)
+	}
 
-	return nil
+	return &data, nil
 }
 
 // preparePayload 准备请求体
-func (c *MemoryClient) preparePayload(messages interface{}, options types.MemoryOptions) (map[string]interface{}, error) {
+func (c *MemoryClient) preparePayload(ctx context.Context, messages interface{}, options types.AddOptions) (map[string]interface{}, error) {
+	if err := c.validate(options); err != nil {
+		return nil, err
+	}
+
+	if err := c.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
 	payload := make(map[string]interface{})
 
 	switch m := messages.(type) {
@@ -144,10 +201,10 @@ func (c *MemoryClient) preparePayload(messages interface{}, options types.Memory
 	case []types.Message:
 		payload["messages"] = m
 	default:
-		return nil, errors.New("invalid messages type")
+		return nil, errors.Wrap(ErrValidation, "invalid messages type")
 	}
 
-	if c.organizationID != "" && c.projectID != "" {
+	if options.OrgID == "" && options.ProjectID == "" {
 		options.OrgID = c.organizationID
 		options.ProjectID = c.projectID
 	}
@@ -168,7 +225,7 @@ func (c *MemoryClient) preparePayload(messages interface{}, options types.Memory
 	}
 
 	if _, ok := payload["messages"]; !ok {
-		return nil, errors.New("messages field is required")
+		return nil, errors.Wrap(ErrValidation, "messages field is required")
 	}
 
 	if options.Version.IsDefault() {
@@ -178,86 +235,226 @@ func (c *MemoryClient) preparePayload(messages interface{}, options types.Memory
 	return payload, nil
 }
 
-// doRequest 执行 HTTP 请求
-func (c *MemoryClient) doRequest(method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
+// validate 在发送请求前校验选项, 可以使用 WithValidation(false) 关闭
+func (c *MemoryClient) validate(v interface{ Validate() error }) error {
+	if c.skipValidation {
+		return nil
+	}
+	return v.Validate()
+}
+
+// pingPath 是 ping 接口的路径
+const pingPath = "/v1/ping/"
+
+// call 执行一次 API 调用: 依次经过拦截器, 发送请求并将成功的响应解码到 out 中
+// out 为 nil 时忽略响应体, 非 2xx 的响应会返回 *APIError
+func (c *MemoryClient) call(ctx context.Context, operation, method, path string, body, out interface{}) error {
+	_, err := c.do(ctx, operation, method, path, body, out)
+	return err
+}
+
+// callWithAttempts 与 call 相同, 但不解码响应, 并返回发送 HTTP 请求的次数
+func (c *MemoryClient) callWithAttempts(ctx context.Context, operation, method, path string, body interface{}) (int, error) {
+	resp, err := c.do(ctx, operation, method, path, body, nil)
+	if resp == nil {
+		return 0, err
+	}
+	return resp.Attempts, err
+}
+
+// do 执行一次 API 调用并返回拦截器链返回的 Response
+func (c *MemoryClient) do(ctx context.Context, operation, method, path string, body, out interface{}) (*Response, error) {
+	if path != pingPath {
+		if err := c.ensureReady(ctx); err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.host, path), reqBody)
+	req := &Request{
+		Operation: operation,
+		Method:    method,
+		Path:      path,
+		Header:    make(http.Header),
+		Body:      body,
+		out:       out,
+	}
+
+	return c.handler(ctx, req)
+}
+
+// roundTrip 是拦截器链末端的 Handler, 负责发送请求并解码响应
+func (c *MemoryClient) roundTrip(ctx context.Context, req *Request) (*Response, error) {
+	httpResp, attempts, err := c.doRequest(ctx, req)
 	if err != nil {
-		return nil, err
+		return &Response{Attempts: attempts}, err
 	}
+	defer httpResp.Body.Close()
 
-	req.Header.Set("Authorization", fmt.Sprintf("Token %s", c.apiKey))
-	req.Header.Set("Content-Type", "application/json")
-	if c.telemetryID != "" {
-		req.Header.Set("Mem0-User-ID", c.telemetryID)
+	resp := &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Attempts:   attempts,
+	}
+
+	resp.Body, err = io.ReadAll(httpResp.Body)
+	if err != nil {
+		return resp, contextError(ctx, errors.Wrap(err, "failed to read response"))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, newAPIError(req.Method, req.Path, httpResp, resp.Body)
+	}
+
+	if req.out == nil || len(resp.Body) == 0 {
+		return resp, nil
+	}
+
+	if err := json.Unmarshal(resp.Body, req.out); err != nil {
+		return resp, errors.Wrap(err, "failed to unmarshal response")
 	}
+	resp.Result = req.out
 
-	return c.client.Do(req)
+	return resp, nil
 }
 
-// AddAsync adds a new memory asynchronously
-// `messages` can be a string, []string, types.Message, or []types.Message
-// Returns an event whose status can be used to track the outcome of the memory addition
-func (c *MemoryClient) AddAsync(messages interface{}, options types.MemoryOptions) ([]types.MemoryAddAEvent, error) {
-	payload, err := c.preparePayload(messages, options)
+// doRequest 执行 HTTP 请求
+// 如果配置了重试策略, 可重试的失败会按策略重试, 返回最后一次尝试的响应和尝试次数
+func (c *MemoryClient) doRequest(ctx context.Context, req *Request) (*http.Response, int, error) {
+	var jsonBody []byte
+	if req.Body != nil {
+		var err error
+		jsonBody, err = json.Marshal(req.Body)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	maxAttempts := c.retry.maxAttempts(isIdempotent(req.Method, req.Path))
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.wait(ctx, req); err != nil {
+			return nil, attempt - 1, contextError(ctx, err)
+		}
+
+		resp, err := c.send(ctx, req, jsonBody)
+		c.limiter.observe(req, resp)
+		if attempt >= maxAttempts {
+			return resp, attempt, err
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if !c.retry.retryError(err) {
+				return nil, attempt, err
+			}
+			wait = c.retry.backoff(attempt)
+		case c.retry.retryStatus(resp.StatusCode):
+			wait = c.retry.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = retryAfter
+			}
+			// 丢弃响应体以便复用连接
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		default:
+			return resp, attempt, nil
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, attempt, contextError(ctx, err)
+		}
+	}
+}
+
+// send 发送一次 HTTP 请求
+func (c *MemoryClient) send(ctx context.Context, r *Request, jsonBody []byte) (*http.Response, error) {
+	var reqBody io.Reader
+	if jsonBody != nil {
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, fmt.Sprintf("%s%s%s", c.host, c.basePath, r.Path), reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest("POST", "/v1/memories/", payload)
+	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token %s", c.apiKey))
+	if c.telemetryID != "" {
+		req.Header.Set("Mem0-User-ID", c.telemetryID)
+	}
+
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, contextError(ctx, err)
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	return resp, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+// cursorPath 将分页接口返回的 next/previous URL 转换为相对于 host 和 base path 的路径
+func (c *MemoryClient) cursorPath(cursor string) string {
+	u, err := url.Parse(cursor)
+	if err != nil || !u.IsAbs() {
+		return cursor
 	}
 
-	var events []types.MemoryAddAEvent
-	if err := json.Unmarshal(body, &events); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	path := u.RequestURI()
+	if c.basePath != "" && strings.HasPrefix(path, c.basePath+"/") {
+		path = strings.TrimPrefix(path, c.basePath)
 	}
+	return path
+}
 
-	return events, nil
+// contextError 在请求因 ctx 取消或超时失败时返回可识别的错误,
+// 使调用方可以使用 errors.Is(err, context.Canceled) 或
+// errors.Is(err, context.DeadlineExceeded) 进行判断
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return errors.Wrap(ctxErr, "request aborted")
+	}
+	return err
 }
 
-// Add adds a new memory synchronously
+// AddAsyncContext adds a new memory asynchronously, using ctx to cancel the request or bound its duration
 // `messages` can be a string, []string, types.Message, or []types.Message
-// Returns the created memories
-func (c *MemoryClient) Add(messages interface{}, options types.MemoryOptions) ([]types.Memory, error) {
-	payload, err := c.preparePayload(messages, options)
+// Returns an event whose status can be used to track the outcome of the memory addition
+func (c *MemoryClient) AddAsyncContext(ctx context.Context, messages interface{}, options types.AddOptions) ([]types.MemoryAddAEvent, error) {
+	payload, err := c.preparePayload(ctx, messages, options)
 	if err != nil {
 		return nil, err
 	}
-	payload["async_mode"] = false
 
-	resp, err := c.doRequest("POST", "/v1/memories/", payload)
-	if err != nil {
+	var events []types.MemoryAddAEvent
+	if err := c.call(ctx, "AddAsync", "POST", "/v1/memories/", payload, &events); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
+	return events, nil
+}
 
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+// AddContext adds a new memory synchronously, using ctx to cancel the request or bound its duration
+// `messages` can be a string, []string, types.Message, or []types.Message
+// Returns the created memories
+func (c *MemoryClient) AddContext(ctx context.Context, messages interface{}, options types.AddOptions) ([]types.Memory, error) {
+	payload, err := c.preparePayload(ctx, messages, options)
+	if err != nil {
+		return nil, err
 	}
+	payload["async_mode"] = false
 
 	var memories []types.Memory
-	if err := json.Unmarshal(body, &memories); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "Add", "POST", "/v1/memories/", payload, &memories); err != nil {
+		return nil, err
 	}
 
 	return memories, nil
@@ -265,25 +462,18 @@ func (c *MemoryClient) Add(messages interface{}, options types.MemoryOptions) ([
 
 // Update 更新内存
 func (c *MemoryClient) Update(memoryID string, message string) ([]types.Memory, error) {
+	return c.UpdateContext(context.Background(), memoryID, message)
+}
+
+// UpdateContext 与 Update 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) UpdateContext(ctx context.Context, memoryID string, message string) ([]types.Memory, error) {
 	payload := map[string]string{
 		"text": message,
 	}
 
-	resp, err := c.doRequest("PUT", fmt.Sprintf("/v1/memories/%s/", memoryID), payload)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
-
 	var memories []types.Memory
-	if err := json.Unmarshal(body, &memories); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "Update", "PUT", fmt.Sprintf("/v1/memories/%s/", memoryID), payload, &memories); err != nil {
+		return nil, err
 	}
 
 	return memories, nil
@@ -291,27 +481,22 @@ func (c *MemoryClient) Update(memoryID string, message string) ([]types.Memory,
 
 // Get 获取内存
 func (c *MemoryClient) Get(memoryID string) (*types.Memory, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/v1/memories/%s/", memoryID), nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.GetContext(context.Background(), memoryID)
+}
 
+// GetContext 与 Get 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) GetContext(ctx context.Context, memoryID string) (*types.Memory, error) {
 	var memory types.Memory
-	if err := json.Unmarshal(body, &memory); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "Get", "GET", fmt.Sprintf("/v1/memories/%s/", memoryID), nil, &memory); err != nil {
+		return nil, err
 	}
 
 	return &memory, nil
 }
 
-func (c *MemoryClient) GetAll(options *types.SearchOptions) ([]types.Memory, error) {
+// GetAllContext lists the memories matching the filters in options using the v2 API,
+// using ctx to cancel the request or bound its duration
+func (c *MemoryClient) GetAllContext(ctx context.Context, options *types.ListOptions) ([]types.Memory, error) {
 	path := "/v2/memories/"
 
 	type getAllRequest struct {
@@ -324,6 +509,16 @@ func (c *MemoryClient) GetAll(options *types.SearchOptions) ([]types.Memory, err
 		Categories map[string][]string `json:"categories,omitempty"`
 	}
 
+	if options != nil {
+		if err := c.validate(options); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
 	var req getAllRequest
 
 	if options != nil {
@@ -331,7 +526,7 @@ func (c *MemoryClient) GetAll(options *types.SearchOptions) ([]types.Memory, err
 			Page:      options.Page,
 			PageSize:  options.PageSize,
 			Fields:    options.Fields,
-			Filters:   options.Filters,
+			Filters:   copyFilters(options.Filters),
 			OrgID:     options.OrgID,
 			ProjectID: options.ProjectID,
 		}
@@ -351,10 +546,11 @@ func (c *MemoryClient) GetAll(options *types.SearchOptions) ([]types.Memory, err
 			req.Filters = fixAPIV2Filters(req.Filters)
 		}
 
-		_, hasAgentID := req.Filters["agent_id"]
-		_, hasUserID := req.Filters["user_id"]
-		if hasAgentID && hasUserID {
-			return nil, errors.New("agent_id and user_id cannot be used together")
+		// fixAPIV2Filters 填充的通配符不限定作用域
+		agentID, hasAgentID := req.Filters["agent_id"]
+		userID, hasUserID := req.Filters["user_id"]
+		if hasAgentID && hasUserID && agentID != types.SearchWildcard && userID != types.SearchWildcard {
+			return nil, errors.Wrap(ErrValidation, "agent_id and user_id cannot be used together")
 		}
 
 		if len(options.Categories) > 0 {
@@ -362,41 +558,41 @@ func (c *MemoryClient) GetAll(options *types.SearchOptions) ([]types.Memory, err
 				"in": options.Categories,
 			}
 			if _, hasCategories := req.Filters["categories"]; hasCategories {
-				return nil, errors.New("categories must be specified outside of filters and inside SearchOptions")
+				return nil, errors.Wrap(ErrValidation, "categories must be specified outside of filters and inside ListOptions")
 			}
 			req.Filters["categories"] = req.Categories
 		}
 
 	}
 
-	resp, err := c.doRequest("POST", path, req)
-	if err != nil {
+	var memories []types.Memory
+	if err := c.call(ctx, "GetAll", "POST", path, req, &memories); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	return memories, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+// SearchContext performs a semantic search over memories using the v2 API,
+// using ctx to cancel the request or bound its duration
+func (c *MemoryClient) SearchContext(ctx context.Context, query string, options *types.QueryOptions) ([]types.Memory, error) {
+	var opts types.QueryOptions
+	if options != nil {
+		opts = *options
 	}
 
-	var memories []types.Memory
-	if err := json.Unmarshal(body, &memories); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.validate(opts); err != nil {
+		return nil, err
 	}
 
-	return memories, nil
+	return c.search(ctx, query, opts, opts.Version)
 }
 
-func (c *MemoryClient) Search(query string, options *types.SearchOptions) ([]types.Memory, error) {
-	if options == nil {
-		options = &types.SearchOptions{}
-	}
-
-	if c.organizationID != "" && c.projectID != "" {
-		options.OrgID = c.organizationID
-		options.ProjectID = c.projectID
+// search 将 options 的 JSON 字段合并到搜索请求中并发送
+// OrgID 和 ProjectID 都为空时使用客户端的组织和项目
+func (c *MemoryClient) search(ctx context.Context, query string, options interface{}, version types.APIVersion) ([]types.Memory, error) {
+	if err := c.ensureReady(ctx); err != nil {
+		return nil, err
 	}
 
 	payload := map[string]interface{}{
@@ -418,105 +614,112 @@ func (c *MemoryClient) Search(query string, options *types.SearchOptions) ([]typ
 			payload[k] = v
 		}
 	}
-	if filters, ok := payload["filters"]; ok && options.Version.IsDefault() {
-		payload["filters"] = fixAPIV2Filters(filters.(map[string]any))
-		payload["filter_memories"] = true
-	}
 
-	resp, err := c.doRequest("POST", "/v2/memories/search/", payload)
-	if err != nil {
-		return nil, err
+	_, hasOrgID := payload["org_id"]
+	_, hasProjectID := payload["project_id"]
+	if !hasOrgID && !hasProjectID {
+		if c.organizationID != "" {
+			payload["org_id"] = c.organizationID
+		}
+		if c.projectID != "" {
+			payload["project_id"] = c.projectID
+		}
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+	if filters, ok := payload["filters"]; ok && version.IsDefault() {
+		payload["filters"] = fixAPIV2Filters(filters.(map[string]any))
+		payload["filter_memories"] = true
 	}
 
 	var memories []types.Memory
-	if err := json.Unmarshal(body, &memories); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "Search", "POST", "/v2/memories/search/", payload, &memories); err != nil {
+		return nil, err
 	}
 
 	return memories, nil
 }
 
+// fixAPIV2Filters 返回过滤条件的副本, 不修改 filters
+// 根是 AND, OR 或 NOT 的过滤条件 (例如 filters.Build 组合出的条件) 原样发送
 func fixAPIV2Filters(filters map[string]any) map[string]any {
-	if filters == nil {
-		filters = make(map[string]any)
+	fixed := copyFilters(filters)
+	if fixed == nil {
+		fixed = make(map[string]any)
+	}
+	if hasLogicalRoot(fixed) {
+		return fixed
 	}
 	// Avoid failing the query for missing fields.
 	// Instead, fill with a wildcard.
-	if _, ok := filters["user_id"]; !ok {
-		filters["user_id"] = types.SearchWildcard
+	if _, ok := fixed["user_id"]; !ok {
+		fixed["user_id"] = types.SearchWildcard
 	}
-	if _, ok := filters["app_id"]; !ok {
-		filters["app_id"] = types.SearchWildcard
+	if _, ok := fixed["app_id"]; !ok {
+		fixed["app_id"] = types.SearchWildcard
 	}
-	if _, ok := filters["run_id"]; !ok {
-		filters["run_id"] = types.SearchWildcard
+	if _, ok := fixed["run_id"]; !ok {
+		fixed["run_id"] = types.SearchWildcard
 	}
-	return filters
+	return fixed
 }
 
-// Delete 删除内存
-func (c *MemoryClient) Delete(memoryID string) error {
-	resp, err := c.doRequest("DELETE", fmt.Sprintf("/v1/memories/%s/", memoryID), nil)
-	if err != nil {
-		return err
+// hasLogicalRoot 判断过滤条件的顶层是否包含 AND, OR 或 NOT
+func hasLogicalRoot(filters map[string]any) bool {
+	for _, op := range []string{"AND", "OR", "NOT"} {
+		if _, ok := filters[op]; ok {
+			return true
+		}
 	}
-	defer resp.Body.Close()
+	return false
+}
 
-	if resp.StatusCode != http.StatusOK {
+// copyFilters 浅拷贝过滤条件
+func copyFilters(filters map[string]any) map[string]any {
+	if filters == nil {
+		return nil
+	}
 
-		body, _ := io.ReadAll(resp.Body)
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+	copied := make(map[string]any, len(filters))
+	for k, v := range filters {
+		copied[k] = v
 	}
+	return copied
+}
 
-	return nil
+// Delete 删除内存
+func (c *MemoryClient) Delete(memoryID string) error {
+	return c.DeleteContext(context.Background(), memoryID)
 }
 
-// DeleteAll 删除所有内存
-func (c *MemoryClient) DeleteAll(options types.MemoryOptions) error {
-	path := "/v1/memories/"
-	if query := options.ToQuery(); query != "" {
-		path += "?" + query
-	}
+// DeleteContext 与 Delete 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) DeleteContext(ctx context.Context, memoryID string) error {
+	return c.call(ctx, "Delete", "DELETE", fmt.Sprintf("/v1/memories/%s/", memoryID), nil, nil)
+}
 
-	resp, err := c.doRequest("DELETE", path, nil)
-	if err != nil {
+// DeleteAllContext 删除所有匹配 options 的内存, 使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) DeleteAllContext(ctx context.Context, options types.DeleteAllOptions) error {
+	if err := c.validate(options); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+	path := "/v1/memories/"
+	if query := options.ToQuery(); query != "" {
+		path += "?" + query
 	}
 
-	return nil
+	return c.call(ctx, "DeleteAll", "DELETE", path, nil, nil)
 }
 
 // History 获取内存历史
 func (c *MemoryClient) History(memoryID string) ([]types.MemoryHistory, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/v1/memories/%s/history/", memoryID), nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.HistoryContext(context.Background(), memoryID)
+}
 
+// HistoryContext 与 History 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) HistoryContext(ctx context.Context, memoryID string) ([]types.MemoryHistory, error) {
 	var history []types.MemoryHistory
-	if err := json.Unmarshal(body, &history); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "History", "GET", fmt.Sprintf("/v1/memories/%s/history/", memoryID), nil, &history); err != nil {
+		return nil, err
 	}
 
 	return history, nil
@@ -524,21 +727,19 @@ func (c *MemoryClient) History(memoryID string) ([]types.MemoryHistory, error) {
 
 // Users 获取所有用户
 func (c *MemoryClient) Users() (*types.AllUsers, error) {
-	resp, err := c.doRequest("GET", "/v1/users/", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
+	return c.UsersContext(context.Background())
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+// UsersContext 与 Users 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) UsersContext(ctx context.Context) (*types.AllUsers, error) {
+	return c.usersPage(ctx, "/v1/users/")
+}
 
+// usersPage 获取一页用户, path 可以是上一页返回的 Next URL
+func (c *MemoryClient) usersPage(ctx context.Context, path string) (*types.AllUsers, error) {
 	var users types.AllUsers
-	if err := json.Unmarshal(body, &users); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "Users", "GET", c.cursorPath(path), nil, &users); err != nil {
+		return nil, err
 	}
 
 	return &users, nil
@@ -546,94 +747,63 @@ func (c *MemoryClient) Users() (*types.AllUsers, error) {
 
 // DeleteUser 删除用户
 func (c *MemoryClient) DeleteUser(entityID string) error {
-	resp, err := c.doRequest("DELETE", fmt.Sprintf("/v1/users/%s/", entityID), nil)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.DeleteUserContext(context.Background(), entityID)
+}
 
-	return nil
+// DeleteUserContext 与 DeleteUser 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) DeleteUserContext(ctx context.Context, entityID string) error {
+	return c.call(ctx, "DeleteUser", "DELETE", fmt.Sprintf("/v1/users/%s/", entityID), nil, nil)
 }
 
 // DeleteUsers 删除所有用户
 func (c *MemoryClient) DeleteUsers() error {
-	resp, err := c.doRequest("DELETE", "/v1/users/", nil)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.DeleteUsersContext(context.Background())
+}
 
-	return nil
+// DeleteUsersContext 与 DeleteUsers 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) DeleteUsersContext(ctx context.Context) error {
+	return c.call(ctx, "DeleteUsers", "DELETE", "/v1/users/", nil, nil)
 }
 
-// BatchUpdate 批量更新内存
+// BatchUpdate 批量更新内存, 超过 DefaultBatchSize 的部分会自动分块发送
 func (c *MemoryClient) BatchUpdate(memories []types.MemoryUpdateBody) error {
-	resp, err := c.doRequest("PUT", "/v1/memories/batch/", memories)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.BatchUpdateContext(context.Background(), memories)
+}
 
-	return nil
+// BatchUpdateContext 与 BatchUpdate 相同, 但使用 ctx 控制请求的取消和超时
+// 需要每个条目的结果时使用 BatchUpdateWithOptions
+func (c *MemoryClient) BatchUpdateContext(ctx context.Context, memories []types.MemoryUpdateBody) error {
+	_, err := c.BatchUpdateWithOptions(ctx, memories, BatchOptions{})
+	return err
 }
 
-// BatchDelete 批量删除内存
+// BatchDelete 批量删除内存, 超过 DefaultBatchSize 的部分会自动分块发送
 func (c *MemoryClient) BatchDelete(memoryIDs []string) error {
-	resp, err := c.doRequest("DELETE", "/v1/memories/batch/", memoryIDs)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.BatchDeleteContext(context.Background(), memoryIDs)
+}
 
-	return nil
+// BatchDeleteContext 与 BatchDelete 相同, 但使用 ctx 控制请求的取消和超时
+// 需要每个条目的结果时使用 BatchDeleteWithOptions
+func (c *MemoryClient) BatchDeleteContext(ctx context.Context, memoryIDs []string) error {
+	_, err := c.BatchDeleteWithOptions(ctx, memoryIDs, BatchOptions{})
+	return err
 }
 
 // GetProject 获取项目
 func (c *MemoryClient) GetProject(options types.ProjectOptions) (*types.ProjectResponse, error) {
+	return c.GetProjectContext(context.Background(), options)
+}
+
+// GetProjectContext 与 GetProject 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) GetProjectContext(ctx context.Context, options types.ProjectOptions) (*types.ProjectResponse, error) {
 	path := "/v1/project/"
 	if query := options.ToQuery(); query != "" {
 		path += "?" + query
 	}
 
-	resp, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
-
 	var project types.ProjectResponse
-	if err := json.Unmarshal(body, &project); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "GetProject", "GET", path, nil, &project); err != nil {
+		return nil, err
 	}
 
 	return &project, nil
@@ -641,75 +811,56 @@ func (c *MemoryClient) GetProject(options types.ProjectOptions) (*types.ProjectR
 
 // UpdateProject 更新项目
 func (c *MemoryClient) UpdateProject(payload types.PromptUpdatePayload) error {
-	resp, err := c.doRequest("PUT", "/v1/project/", payload)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.UpdateProjectContext(context.Background(), payload)
+}
 
-	return nil
+// UpdateProjectContext 与 UpdateProject 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) UpdateProjectContext(ctx context.Context, payload types.PromptUpdatePayload) error {
+	return c.call(ctx, "UpdateProject", "PUT", "/v1/project/", payload, nil)
 }
 
 // GetWebhooks 获取 Webhooks
 func (c *MemoryClient) GetWebhooks(projectID string) ([]types.Webhook, error) {
+	return c.GetWebhooksContext(context.Background(), projectID)
+}
+
+// GetWebhooksContext 与 GetWebhooks 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) GetWebhooksContext(ctx context.Context, projectID string) ([]types.Webhook, error) {
 	path := "/v1/webhooks/"
 	if projectID != "" {
 		path += "?project_id=" + projectID
 	}
 
-	resp, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
-
 	var webhooks []types.Webhook
-	if err := json.Unmarshal(body, &webhooks); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "GetWebhooks", "GET", path, nil, &webhooks); err != nil {
+		return nil, err
 	}
 
 	return webhooks, nil
 }
 
-var (
-	ErrDuplicateWebhook = errors.New("a webhook with the same project and url already exists")
-)
-
+// CreateWebhook creates a webhook for the given project
+// Returns ErrDuplicateWebhook if a webhook with the same url already exists
 func (c *MemoryClient) CreateWebhook(projectID string, webhook types.WebhookPayload) (*types.Webhook, error) {
+	return c.CreateWebhookContext(context.Background(), projectID, webhook)
+}
+
+// CreateWebhookContext is like CreateWebhook but uses ctx to cancel the request or bound its duration
+func (c *MemoryClient) CreateWebhookContext(ctx context.Context, projectID string, webhook types.WebhookPayload) (*types.Webhook, error) {
 	if projectID == "" {
-		return nil, errors.New("project_id is required")
+		return nil, errors.Wrap(ErrValidation, "project_id is required")
 	}
-	resp, err := c.doRequest("POST", fmt.Sprintf("/api/v1/webhooks/projects/%s/", projectID), webhook)
-	if err != nil {
+	if err := c.validate(webhook); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusCreated {
-		if resp.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "must make a unique set.") {
-			return nil, ErrDuplicateWebhook
-		}
-
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
 
 	var createdWebhook types.Webhook
-	if err := json.Unmarshal(body, &createdWebhook); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "CreateWebhook", "POST", fmt.Sprintf("/api/v1/webhooks/projects/%s/", projectID), webhook, &createdWebhook); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && strings.Contains(apiErr.Body, "must make a unique set.") {
+			apiErr.Err = ErrDuplicateWebhook
+		}
+		return nil, err
 	}
 
 	return &createdWebhook, nil
@@ -717,99 +868,74 @@ func (c *MemoryClient) CreateWebhook(projectID string, webhook types.WebhookPayl
 
 // UpdateWebhook 更新 Webhook
 func (c *MemoryClient) UpdateWebhook(webhook types.WebhookPayload) error {
-	resp, err := c.doRequest("PUT", "/v1/webhooks/", webhook)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
+	return c.UpdateWebhookContext(context.Background(), webhook)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+// UpdateWebhookContext 与 UpdateWebhook 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) UpdateWebhookContext(ctx context.Context, webhook types.WebhookPayload) error {
+	if webhook.WebhookID == "" {
+		return errors.Wrap(ErrValidation, "webhook_id is required")
 	}
-
-	return nil
+	if err := c.validate(webhook); err != nil {
+		return err
+	}
+	return c.call(ctx, "UpdateWebhook", "PUT", "/v1/webhooks/", webhook, nil)
 }
 
 // DeleteWebhook 删除 Webhook
 func (c *MemoryClient) DeleteWebhook(webhookID string) error {
-	resp, err := c.doRequest("DELETE", fmt.Sprintf("/v1/webhooks/%s/", webhookID), nil)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.DeleteWebhookContext(context.Background(), webhookID)
+}
 
-	return nil
+// DeleteWebhookContext 与 DeleteWebhook 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) DeleteWebhookContext(ctx context.Context, webhookID string) error {
+	return c.call(ctx, "DeleteWebhook", "DELETE", fmt.Sprintf("/v1/webhooks/%s/", webhookID), nil, nil)
 }
 
 // Feedback 提交反馈
 func (c *MemoryClient) Feedback(payload types.FeedbackPayload) error {
-	resp, err := c.doRequest("POST", "/v1/feedback/", payload)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
+	return c.FeedbackContext(context.Background(), payload)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+// FeedbackContext 与 Feedback 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) FeedbackContext(ctx context.Context, payload types.FeedbackPayload) error {
+	if err := c.validate(payload); err != nil {
+		return err
 	}
-
-	return nil
+	return c.call(ctx, "Feedback", "POST", "/v1/feedback/", payload, nil)
 }
 
 // GetEvent 获取事件
 func (c *MemoryClient) GetEvent(eventID string) (*types.Event, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/v1/event/%s/", eventID), nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
-	}
+	return c.GetEventContext(context.Background(), eventID)
+}
 
+// GetEventContext 与 GetEvent 相同, 但使用 ctx 控制请求的取消和超时
+func (c *MemoryClient) GetEventContext(ctx context.Context, eventID string) (*types.Event, error) {
 	var event types.Event
-	if err := json.Unmarshal(body, &event); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "GetEvent", "GET", fmt.Sprintf("/v1/event/%s/", eventID), nil, &event); err != nil {
+		return nil, err
 	}
 
 	return &event, nil
 }
 
+// GetEvents lists events. `cursor` is the `Next` URL of a previous page, or empty for the first page
 func (c *MemoryClient) GetEvents(cursor string) (*types.GetEventsResponse, error) {
+	return c.GetEventsContext(context.Background(), cursor)
+}
+
+// GetEventsContext is like GetEvents but uses ctx to cancel the request or bound its duration
+func (c *MemoryClient) GetEventsContext(ctx context.Context, cursor string) (*types.GetEventsResponse, error) {
 	path := "/v1/events/"
 	if cursor != "" {
 		// Cursor is the URL
-		path = cursor
-	}
-
-	resp, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get events")
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{Message: fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(body))}
+		path = c.cursorPath(cursor)
 	}
 
 	var events types.GetEventsResponse
-	if err := json.Unmarshal(body, &events); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	if err := c.call(ctx, "GetEvents", "GET", path, nil, &events); err != nil {
+		return nil, err
 	}
 
 	return &events, nil
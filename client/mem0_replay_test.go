@@ -0,0 +1,306 @@
+////////////////////////////////////////////////////////////
+// Replay tests for the client's request encoding and response decoding.
+//
+// The committed cassettes in testdata/cassettes were recorded against the
+// in-process mem0test server (TEST_MEM0_FAKE=1), so they reflect the fake's
+// behaviour and are not acceptance coverage of the live Mem0 API.
+//
+// Tests replay their cassette offline when it exists, record it when
+// TEST_MEM0_API_KEY or TEST_MEM0_FAKE is set, and are skipped otherwise.
+// API keys and emails are scrubbed before a cassette is written.
+////////////////////////////////////////////////////////////
+
+package client_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/cassette"
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/mem0test"
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mem0ApiKey string
+	projectID  string
+	memID      string
+	// mem0Host 为空时使用默认的 Mem0 API 地址
+	mem0Host string
+
+	cassetteMode cassette.Mode
+)
+
+// volatileFields 是每次运行都会变化的请求字段, 回放时不参与匹配
+var volatileFields = []string{
+	"metadata.timestamp",
+	"filters.created_at",
+	"filters.AND.created_at",
+}
+
+const (
+	userID        = "test-gosdk-user"
+	agentID       = "test-gosdk-agent"
+	appID         = "test-gosdk-app"
+	runID         = "test-gosdk-run"
+	metadataKeyID = "test-gosdk-metadata-key-id"
+)
+
+func TestMain(m *testing.M) {
+	if err := godotenv.Load(); err != nil {
+		envPath := filepath.Join("..", ".env")
+		if err := godotenv.Load(envPath); err != nil {
+			log.Printf("Warning: could not load .env file: %v", err)
+		}
+	}
+
+	mem0ApiKey = os.Getenv("TEST_MEM0_API_KEY")
+	memID = os.Getenv("TEST_MEM0_MEMORY_ID")
+	projectID = os.Getenv("TEST_MEM0_PROJECT_ID")
+
+	var err error
+	cassetteMode, err = cassette.ParseMode(os.Getenv("TEST_MEM0_CASSETTE_MODE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if os.Getenv("TEST_MEM0_FAKE") == "" {
+		os.Exit(m.Run())
+	}
+
+	// 内存的创建时间早于测试中按秒截断的 created_at 上限
+	srv := mem0test.NewServer(mem0test.WithClock(func() time.Time { return time.Now().Add(-time.Minute) }))
+	mem0Host = srv.URL
+	mem0ApiKey = mem0test.APIKey
+	projectID = mem0test.ProjectID
+	memID = srv.AddMemory(types.Memory{Memory: "Client prefers invoices by email", UserID: userID}).ID
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// newReplayClient 创建通过 cassette 访问 Mem0 的客户端
+// 有 cassette 时离线回放, 没有 cassette 但设置了 TEST_MEM0_API_KEY 时录制, 都没有时跳过测试.
+// TEST_MEM0_CASSETTE_MODE=record 重新录制所有 cassette
+func newReplayClient(t *testing.T) (*client.MemoryClient, *cassette.Recorder) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "cassettes", t.Name()+".yaml")
+	if cassetteMode == cassette.ModeRecord && mem0ApiKey == "" {
+		t.Fatal("TEST_MEM0_API_KEY environment variable is required to record cassettes")
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) && (cassetteMode == cassette.ModeReplay || mem0ApiKey == "") {
+		t.Skipf("no cassette %s and TEST_MEM0_API_KEY is not set", path)
+	}
+
+	rec, err := cassette.New(path, cassette.Options{
+		Mode:         cassetteMode,
+		Secrets:      []string{mem0ApiKey},
+		IgnoreFields: volatileFields,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("Failed to save cassette: %v", err)
+		}
+	})
+
+	apiKey := mem0ApiKey
+	if apiKey == "" {
+		apiKey = "replay"
+	}
+	c, err := client.NewMemoryClient(client.ClientOptions{APIKey: apiKey, Host: mem0Host}, client.WithTransport(rec))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return c, rec
+}
+
+// replayValue 返回录制时由环境变量 env 给出的测试参数, 回放时使用 cassette 中保存的值
+func replayValue(t *testing.T, rec *cassette.Recorder, name, live, env string) string {
+	t.Helper()
+
+	v := rec.Value(name, live)
+	if v == "" {
+		t.Fatalf("%s environment variable is required", env)
+	}
+	return v
+}
+
+func TestReplayAddMemoryAndRetrieveEventAsync(t *testing.T) {
+	client, _ := newReplayClient(t)
+
+	message := types.Message{
+		Role:    "user",
+		Content: "Client was onboarded on 2025-02-03 and is a new customer. He likes churros and ice cream, but not burgers. He LOVES pizza tho",
+	}
+
+	events, err := client.AddAsyncContext(context.Background(), message, types.AddOptions{
+		UserID:  userID,
+		AgentID: agentID,
+		AppID:   appID,
+		RunID:   runID,
+		Metadata: map[string]any{
+			"timestamp":         time.Now().Unix(),
+			"metadata_key_id":   metadataKeyID,
+			"metadata_key_id_2": metadataKeyID,
+		},
+		CustomCategories: types.CustomCategories{
+			{
+				CategoryName:        "payment_terms",
+				CategoryDescription: "Customer preferences on payment terms",
+			},
+			{
+				CategoryName:        "delivery_preferences",
+				CategoryDescription: "Customer preferences on delivery time",
+			},
+			{
+				CategoryName:        "product_preferences",
+				CategoryDescription: "Customer preferences on product",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add memory: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatalf("No add responses")
+	}
+	eventID := events[0].EventID
+
+	event, err := client.GetEvent(eventID)
+	if err != nil {
+		t.Fatalf("Failed to get event: %v", err)
+	}
+	t.Logf("Event: %v", event)
+}
+
+func TestReplayAddMemoryAndRetrieveEventSync(t *testing.T) {
+	client, _ := newReplayClient(t)
+
+	message := types.Message{
+		Role:    "user",
+		Content: "Client enjoys the product and is satisfied with the purchase. Client has 5 delivery orders in the last 12 months. Client has 2 delivery addresses in the last 12 months.",
+	}
+
+	memories, err := client.AddContext(context.Background(), message, types.AddOptions{
+		UserID:  userID,
+		AgentID: agentID,
+		AppID:   appID,
+		RunID:   runID,
+		Metadata: map[string]any{
+			"timestamp":       time.Now().Unix(),
+			"metadata_key_id": metadataKeyID,
+		},
+		Version: types.V2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to add memory: %v", err)
+	}
+	if len(memories) == 0 {
+		t.Fatalf("No add responses")
+	}
+	t.Logf("Memory: %v", memories[0])
+}
+
+func TestReplayGetEvents(t *testing.T) {
+	client, _ := newReplayClient(t)
+
+	events, err := client.GetEvents("")
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	t.Logf("Events: %v", events)
+}
+
+func TestReplayGetMemory(t *testing.T) {
+	client, rec := newReplayClient(t)
+	memoryID := replayValue(t, rec, "memory_id", memID, "TEST_MEM0_MEMORY_ID")
+
+	memory, err := client.Get(memoryID)
+	if err != nil {
+		t.Fatalf("Failed to get memory: %v", err)
+	}
+	t.Logf("Memory: %v", memory)
+}
+
+func TestReplayGetAllMemories(t *testing.T) {
+	client, _ := newReplayClient(t)
+
+	memories, err := client.GetAllContext(context.Background(), &types.ListOptions{
+		PageSize: 1,
+		Filters: map[string]any{
+			"AND": []map[string]any{
+				{
+					"user_id": userID,
+				},
+				{
+					"metadata": map[string]any{
+						"metadata_key_id": metadataKeyID,
+					},
+				},
+				{
+					"created_at": map[string]any{
+						"gte": time.Now().Add(-1 * time.Hour * 24 * 30 * 12).Format(time.RFC3339),
+						"lte": time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+		Categories: []string{
+			"product_preferences",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to get all memories: %v", err)
+	}
+	require.Greater(t, 1, len(memories))
+	t.Logf("Memories: %v", memories)
+}
+
+func TestReplaySearchMemories(t *testing.T) {
+	client, _ := newReplayClient(t)
+
+	memories, err := client.SearchContext(context.Background(), "Does the client enjoy the product?", &types.QueryOptions{
+		TopK: 1,
+		Filters: map[string]any{
+			"user_id": userID,
+			"metadata": map[string]any{
+				"metadata_key_id": metadataKeyID,
+			},
+			"created_at": map[string]any{
+				"gte": time.Now().Add(-1 * time.Hour * 24 * 30 * 12).Format(time.RFC3339),
+				"lte": time.Now().Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to get all memories: %v", err)
+	}
+	require.Equal(t, 1, len(memories))
+	t.Logf("Memories: %v", memories)
+}
+
+func TestReplayCreateWebhook(t *testing.T) {
+	client, rec := newReplayClient(t)
+	project := replayValue(t, rec, "project_id", projectID, "TEST_MEM0_PROJECT_ID")
+
+	webhook, err := client.CreateWebhook(project, types.WebhookPayload{
+		EventTypes: []types.WebhookEvent{types.MemoryUpdated},
+		Name:       "test-webhook",
+		URL:        "https://3.example.com",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create webhook: %v", err)
+	}
+	t.Logf("Webhook: %v", webhook)
+}
@@ -1,16 +1,40 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/bytectlgo/mem0-go/filters"
 	"github.com/bytectlgo/mem0-go/types"
 	"github.com/stretchr/testify/assert"
 )
 
+// newTestServer 创建测试服务器, 自动响应客户端启动时的 ping 请求
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/ping/" {
+			json.NewEncoder(w).Encode(map[string]string{
+				"status":     "ok",
+				"org_id":     "test-org",
+				"project_id": "test-project",
+				"user_email": "test@example.com",
+			})
+			return
+		}
+		handler(w, r)
+	}))
+}
+
 func TestNewMemoryClient(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
 	// 测试无效的 API key
 	_, err := NewMemoryClient(ClientOptions{})
 	assert.Error(t, err)
@@ -18,14 +42,17 @@ func TestNewMemoryClient(t *testing.T) {
 	// 测试有效的客户端创建
 	client, err := NewMemoryClient(ClientOptions{
 		APIKey: "test-key",
+		Host:   server.URL,
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, client)
+	assert.Equal(t, "test-org", client.organizationID)
+	assert.Equal(t, "test-project", client.projectID)
 }
 
 func TestAddMemory(t *testing.T) {
 	// 创建测试服务器
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "POST", r.Method)
 		assert.Equal(t, "/v1/memories/", r.URL.Path)
 		assert.Equal(t, "Token test-key", r.Header.Get("Authorization"))
@@ -39,7 +66,7 @@ func TestAddMemory(t *testing.T) {
 			},
 		}
 		json.NewEncoder(w).Encode(response)
-	}))
+	})
 	defer server.Close()
 
 	// 创建客户端
@@ -49,13 +76,42 @@ func TestAddMemory(t *testing.T) {
 	})
 
 	// 测试添加内存
-	_, err := client.Add("test memory", types.MemoryOptions{})
+	_, err := client.AddContext(context.Background(), "test memory", types.AddOptions{UserID: "test-user"})
+	assert.NoError(t, err)
+}
+
+func TestAddOptions(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, false, payload["infer"])
+		assert.Equal(t, "alice", payload["user_id"])
+		assert.Equal(t, "my-org", payload["org_id"])
+		assert.Equal(t, "my-project", payload["project_id"])
+		assert.NotContains(t, payload, "filters")
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	infer := false
+	_, err = client.AddContext(context.Background(), "test memory", types.AddOptions{
+		UserID:    "alice",
+		Infer:     &infer,
+		OrgID:     "my-org",
+		ProjectID: "my-project",
+	})
 	assert.NoError(t, err)
 }
 
 func TestGetMemory(t *testing.T) {
 	// 创建测试服务器
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
 		assert.Equal(t, "/v1/memories/test-id/", r.URL.Path)
 
@@ -66,7 +122,7 @@ func TestGetMemory(t *testing.T) {
 			UserID: "test-user",
 		}
 		json.NewEncoder(w).Encode(response)
-	}))
+	})
 	defer server.Close()
 
 	// 创建客户端
@@ -84,10 +140,13 @@ func TestGetMemory(t *testing.T) {
 
 func TestSearchMemory(t *testing.T) {
 	// 创建测试服务器
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/v1/memories/search/", r.URL.Path)
-		assert.Equal(t, "test", r.URL.Query().Get("query"))
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/v2/memories/search/", r.URL.Path)
+
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "test", payload["query"])
 
 		// 返回测试响应
 		response := []types.Memory{
@@ -98,7 +157,7 @@ func TestSearchMemory(t *testing.T) {
 			},
 		}
 		json.NewEncoder(w).Encode(response)
-	}))
+	})
 	defer server.Close()
 
 	// 创建客户端
@@ -108,19 +167,69 @@ func TestSearchMemory(t *testing.T) {
 	})
 
 	// 测试搜索内存
-	results, err := client.Search("test", nil)
+	results, err := client.SearchContext(context.Background(), "test", nil)
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
 	assert.Equal(t, "test-id", results[0].ID)
 }
 
+func TestGetAllFilters(t *testing.T) {
+	var got map[string]any
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, []string{"/v2/memories/", "/v2/memories/search/"}, r.URL.Path)
+
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		got, _ = payload["filters"].(map[string]any)
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	assert.NoError(t, err)
+
+	// 只指定 agent_id 时, 填充的 user_id 通配符不应触发作用域检查
+	_, err = client.GetAllContext(context.Background(), &types.ListOptions{Filters: map[string]any{"agent_id": "bot"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "bot", got["agent_id"])
+	assert.Equal(t, types.SearchWildcard, got["user_id"])
+
+	_, err = client.GetAllContext(context.Background(), &types.ListOptions{Filters: map[string]any{"agent_id": "bot", "user_id": "alice"}})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = client.GetAllContext(context.Background(), &types.ListOptions{Filters: filters.MustBuild(filters.And(
+		filters.UserID("alice"),
+		filters.Categories("work"),
+	))})
+	assert.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"user_id": "alice"},
+		map[string]any{"categories": map[string]any{"in": []any{"work"}}},
+	}, got["AND"])
+	// 根是 AND 的过滤条件原样发送, 不填充通配符
+	assert.Len(t, got, 1)
+
+	// 调用方的过滤条件不会被修改
+	conds := map[string]any{"agent_id": "bot"}
+	_, err = client.GetAllContext(context.Background(), &types.ListOptions{Filters: conds, Categories: []string{"work"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"agent_id": "bot"}, conds)
+	_, err = client.SearchContext(context.Background(), "tea", &types.QueryOptions{Filters: conds})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"agent_id": "bot"}, conds)
+}
+
 func TestDeleteMemory(t *testing.T) {
 	// 创建测试服务器
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "DELETE", r.Method)
 		assert.Equal(t, "/v1/memories/test-id/", r.URL.Path)
 		w.WriteHeader(http.StatusOK)
-	}))
+	})
 	defer server.Close()
 
 	// 创建客户端
@@ -133,3 +242,50 @@ func TestDeleteMemory(t *testing.T) {
 	err := client.Delete("test-id")
 	assert.NoError(t, err)
 }
+
+func TestDeleteAllMemories(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/v1/memories/", r.URL.Path)
+		assert.Equal(t, "alice", r.URL.Query().Get("user_id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.DeleteAllContext(context.Background(), types.DeleteAllOptions{UserID: "alice"}))
+}
+
+func TestContextCancellation(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	// 已取消的 ctx
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetContext(ctx, "test-id")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// 超时的 ctx
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.SearchContext(ctx, "test", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
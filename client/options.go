@@ -0,0 +1,106 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHost 是默认的 Mem0 API 地址
+	DefaultHost = "https://api.mem0.ai"
+	// DefaultTimeout 是默认 HTTP 客户端的超时时间
+	DefaultTimeout = 60 * time.Second
+	// DefaultUserAgent 是默认的 User-Agent 请求头
+	DefaultUserAgent = "mem0-go"
+)
+
+// Option 定义客户端的可选配置, 在 ClientOptions 之后依次应用
+type Option func(*MemoryClient)
+
+// WithHTTPClient 使用调用方提供的 HTTP 客户端, 可用于配置代理, TLS 和连接池
+// 后续的 WithTimeout 和 WithTransport 会修改它的副本, 不会影响调用方的实例
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *MemoryClient) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithTransport 设置 HTTP 客户端使用的 RoundTripper, 例如自定义 TLS 或测试用的 transport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *MemoryClient) {
+		httpClient := *c.client
+		httpClient.Transport = transport
+		c.client = &httpClient
+	}
+}
+
+// WithTimeout 设置单次 HTTP 请求的超时时间, 0 表示不超时
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *MemoryClient) {
+		httpClient := *c.client
+		httpClient.Timeout = timeout
+		c.client = &httpClient
+	}
+}
+
+// WithUserAgent 设置 User-Agent 请求头
+func WithUserAgent(userAgent string) Option {
+	return func(c *MemoryClient) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader 为每个请求添加额外的请求头, Authorization 请求头不能被覆盖
+func WithHeader(key, value string) Option {
+	return func(c *MemoryClient) {
+		c.headers.Set(key, value)
+	}
+}
+
+// WithHeaders 为每个请求添加多个额外的请求头, Authorization 请求头不能被覆盖
+func WithHeaders(headers http.Header) Option {
+	return func(c *MemoryClient) {
+		for key, values := range headers {
+			c.headers.Del(key)
+			for _, value := range values {
+				c.headers.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithBasePath 设置添加在 host 和 API 路径之间的前缀, 用于通过反向代理访问 API
+func WithBasePath(basePath string) Option {
+	return func(c *MemoryClient) {
+		basePath = strings.TrimSuffix(basePath, "/")
+		if basePath != "" && !strings.HasPrefix(basePath, "/") {
+			basePath = "/" + basePath
+		}
+		c.basePath = basePath
+	}
+}
+
+// WithRetryPolicy 设置失败请求的重试策略, 覆盖 ClientOptions.Retry
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *MemoryClient) {
+		c.retry = policy
+	}
+}
+
+// WithPingMode 设置何时调用 /v1/ping/, 覆盖 ClientOptions.PingMode
+func WithPingMode(mode PingMode) Option {
+	return func(c *MemoryClient) {
+		c.pingMode = mode
+	}
+}
+
+// WithValidation 设置是否在发送请求前校验选项, 默认开启
+// 关闭后无效的选项会原样发送, 由服务端决定如何处理
+func WithValidation(enabled bool) Option {
+	return func(c *MemoryClient) {
+		c.skipValidation = !enabled
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripFunc 允许使用函数作为 http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// jsonResponse 创建一个 JSON 响应
+func jsonResponse(status int, v interface{}) *http.Response {
+	body, _ := json.Marshal(v)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	var paths []string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "fake.mem0.local", r.URL.Host)
+		if strings.HasSuffix(r.URL.Path, "/v1/ping/") {
+			return jsonResponse(http.StatusOK, map[string]string{"status": "ok"}), nil
+		}
+		return jsonResponse(http.StatusOK, types.Memory{ID: "test-id"}), nil
+	})
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   "https://fake.mem0.local/",
+	}, WithTransport(transport), WithBasePath("proxy/mem0/"))
+	assert.NoError(t, err)
+
+	memory, err := client.Get("test-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", memory.ID)
+	assert.Equal(t, []string{"/proxy/mem0/v1/ping/", "/proxy/mem0/v1/memories/test-id/"}, paths)
+}
+
+func TestWithHeaders(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-agent/1.0", r.Header.Get("User-Agent"))
+		assert.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		assert.Equal(t, []string{"a", "b"}, r.Header.Values("X-Multi"))
+		assert.Equal(t, "Token test-key", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(types.Memory{ID: "test-id"})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	},
+		WithUserAgent("my-agent/1.0"),
+		WithHeader("X-Tenant", "tenant-a"),
+		WithHeaders(http.Header{
+			"X-Multi":       []string{"a", "b"},
+			"Authorization": []string{"Token other"},
+		}),
+	)
+	assert.NoError(t, err)
+
+	_, err = client.Get("test-id")
+	assert.NoError(t, err)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	}, WithHTTPClient(httpClient), WithTimeout(5*time.Second))
+	assert.NoError(t, err)
+
+	// 调用方的 HTTP 客户端不会被修改
+	assert.Equal(t, time.Minute, httpClient.Timeout)
+	assert.Equal(t, 5*time.Second, client.client.Timeout)
+}
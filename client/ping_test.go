@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingLazy(t *testing.T) {
+	var pings int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test@example.com", r.Header.Get("Mem0-User-ID"))
+
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "test-org", payload["org_id"])
+		assert.Equal(t, "test-project", payload["project_id"])
+		json.NewEncoder(w).Encode([]types.Memory{})
+	})
+	defer server.Close()
+
+	countPings := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == pingPath {
+			atomic.AddInt32(&pings, 1)
+		}
+		countPings.ServeHTTP(w, r)
+	})
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingLazy,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&pings))
+
+	// 并发的首次调用只会 ping 一次
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.SearchContext(context.Background(), "test", nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pings))
+}
+
+func TestPingLazyRetriesAfterFailure(t *testing.T) {
+	var fail int32 = 1
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(types.Memory{ID: "test-id"})
+	})
+	defer server.Close()
+
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == pingPath && atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	}, WithPingMode(PingLazy))
+	assert.NoError(t, err)
+
+	_, err = client.Get("test-id")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	atomic.StoreInt32(&fail, 0)
+	memory, err := client.Get("test-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", memory.ID)
+}
+
+func TestPingNever(t *testing.T) {
+	// 不会访问网络, 因此可以使用不存在的 host
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:         "test-key",
+		Host:           "http://mem0.invalid",
+		OrganizationID: "org",
+		ProjectID:      "project",
+		PingMode:       PingNever,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "org", client.organizationID)
+	assert.Equal(t, "", client.telemetryID)
+}
+
+func TestPing(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	})
+	assert.NoError(t, err)
+
+	resp, err := client.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &types.PingResponse{
+		Status:    "ok",
+		OrgID:     "test-org",
+		ProjectID: "test-project",
+		UserEmail: "test@example.com",
+	}, resp)
+}
@@ -0,0 +1,331 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateClass 是限流器对请求的分类
+type RateClass int
+
+const (
+	// RateRead 包括 GET 请求以及 Search 和 GetAll
+	RateRead RateClass = iota
+	// RateWrite 包括 Add, Update, Delete 等其他请求
+	RateWrite
+)
+
+func (c RateClass) String() string {
+	if c == RateRead {
+		return "read"
+	}
+	return "write"
+}
+
+// rateClassOf 返回请求的分类, Search 和 GetAll 虽然使用 POST, 但只读取数据
+func rateClassOf(method, path string) RateClass {
+	switch {
+	case method == http.MethodGet || method == http.MethodHead:
+		return RateRead
+	case method == http.MethodPost && (path == "/v2/memories/" || path == "/v2/memories/search/"):
+		return RateRead
+	}
+	return RateWrite
+}
+
+// RateBudget 定义一类请求的令牌桶
+type RateBudget struct {
+	// Rate 是每秒补充的令牌数, 0 表示不限制 (仍然遵守服务端返回的限流响应头)
+	Rate float64
+	// Burst 是桶的容量, 即空闲后可以立即发出的请求数, 默认为 1
+	Burst int
+}
+
+// RateLimitOptions 定义读写请求各自的预算
+type RateLimitOptions struct {
+	Read  RateBudget
+	Write RateBudget
+	// OnWait 可选, 每次请求获取到令牌后调用, wait 是在限流器中等待的时间 (可能为 0)
+	// 可用于导出指标, 不应阻塞
+	OnWait func(class RateClass, operation string, wait time.Duration)
+}
+
+// RateLimiter 是按读写分类的客户端令牌桶限流器
+//
+// 同一个 RateLimiter 可以在多个 MemoryClient 之间共享, 使它们共用一个预算
+// 服务端返回 429 (Retry-After) 或 X-RateLimit-Remaining 为 0 (X-RateLimit-Reset) 时,
+// 对应分类的请求会暂停到限流窗口重置
+type RateLimiter struct {
+	buckets [2]*tokenBucket
+	onWait  func(class RateClass, operation string, wait time.Duration)
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(opts RateLimitOptions) *RateLimiter {
+	return &RateLimiter{
+		buckets: [2]*tokenBucket{
+			RateRead:  newTokenBucket(opts.Read.Rate, opts.Read.Burst),
+			RateWrite: newTokenBucket(opts.Write.Rate, opts.Write.Burst),
+		},
+		onWait: opts.OnWait,
+	}
+}
+
+// WithRateLimiter 使用限流器限制客户端发出的请求, 每次重试同样需要获取令牌
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *MemoryClient) {
+		c.limiter = limiter
+	}
+}
+
+// RateClassStats 是一类请求的限流器状态
+type RateClassStats struct {
+	// Requests 是获取过令牌的请求数
+	Requests int64
+	// Waited 是需要等待才能获取令牌的请求数
+	Waited int64
+	// TotalWait 和 MaxWait 是请求在限流器中等待的总时间和最长时间
+	TotalWait time.Duration
+	MaxWait   time.Duration
+	// Throttled 是服务端返回 429 的次数
+	Throttled int64
+	// Tokens 是当前可用的令牌数, 为负数时表示已经预约的请求数; 不限制速率时为 +Inf
+	Tokens float64
+	// PausedUntil 是根据服务端响应头暂停到的时间, 零值表示未暂停
+	PausedUntil time.Time
+}
+
+// RateLimiterStats 是限流器的状态快照
+type RateLimiterStats struct {
+	Read  RateClassStats
+	Write RateClassStats
+}
+
+// Stats 返回限流器的状态快照
+func (l *RateLimiter) Stats() RateLimiterStats {
+	now := time.Now()
+	return RateLimiterStats{
+		Read:  l.buckets[RateRead].stats(now),
+		Write: l.buckets[RateWrite].stats(now),
+	}
+}
+
+// wait 等待 req 所属分类的令牌, l 为 nil 时直接返回
+func (l *RateLimiter) wait(ctx context.Context, req *Request) error {
+	if l == nil {
+		return nil
+	}
+
+	class := rateClassOf(req.Method, req.Path)
+	wait, err := l.buckets[class].wait(ctx)
+	if err == nil && l.onWait != nil {
+		l.onWait(class, req.Operation, wait)
+	}
+	return err
+}
+
+// observe 根据响应的状态码和限流响应头调整 req 所属分类的限流器
+func (l *RateLimiter) observe(req *Request, resp *http.Response) {
+	if l == nil || resp == nil {
+		return
+	}
+
+	bucket := l.buckets[rateClassOf(req.Method, req.Path)]
+	now := time.Now()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		pause, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+		if !ok {
+			pause, ok = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now)
+		}
+		if !ok {
+			pause = time.Second
+		}
+		bucket.throttle(now.Add(pause))
+		return
+	}
+
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil && remaining <= 0 {
+		if reset, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now); ok {
+			bucket.pause(now.Add(reset))
+		}
+	}
+}
+
+// parseRateLimitReset 解析 X-RateLimit-Reset 头, 支持剩余秒数和 Unix 时间戳两种格式
+func parseRateLimitReset(value string, now time.Time) (time.Duration, bool) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	// 大于一年的值视为 Unix 时间戳
+	if seconds > 365*24*60*60 {
+		return time.Unix(int64(seconds), 0).Sub(now), true
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// tokenBucket 是一个令牌桶, 令牌不足时预约未来的令牌并等待
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	// pauses 在每次延长暂停时加一, 用于发现等待期间开始的暂停
+	pauses int
+	stat   RateClassStats
+}
+
+// newTokenBucket 创建每秒补充 rate 个令牌, 容量为 burst 的令牌桶, rate <= 0 时不限制速率
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve 取走一个令牌并返回需要等待的时间和当前的暂停次数
+// 暂停期间 last 位于暂停结束的时刻, 令牌从那时起才开始补充, 排队的请求在暂停结束后按速率依次发出
+func (b *tokenBucket) reserve(now time.Time) (time.Duration, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var wait time.Duration
+	if b.rate > 0 {
+		b.advance(now)
+		b.tokens--
+		if b.last.After(now) {
+			wait = b.last.Sub(now)
+		}
+		if b.tokens < 0 {
+			wait += time.Duration(-b.tokens / b.rate * float64(time.Second))
+		}
+	}
+	if pause := b.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait, b.pauses
+}
+
+// pausedSince 判断 reserve 返回 pauses 之后是否开始了一次仍未结束的暂停
+func (b *tokenBucket) pausedSince(pauses int, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pauses != pauses && b.pausedUntil.After(now)
+}
+
+// acquired 记录一个等待了 wait 后获取到令牌的请求
+func (b *tokenBucket) acquired(wait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stat.Requests++
+	if wait > 0 {
+		b.stat.Waited++
+		b.stat.TotalWait += wait
+		if wait > b.stat.MaxWait {
+			b.stat.MaxWait = wait
+		}
+	}
+}
+
+// cancel 归还 reserve 取走的令牌
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+1)
+	}
+}
+
+// advance 按经过的时间补充令牌, 调用方需持有 mu
+func (b *tokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// wait 等待一个令牌, ctx 结束时归还令牌并返回 ctx 的错误, 取消的等待不计入统计
+func (b *tokenBucket) wait(ctx context.Context) (time.Duration, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	var total time.Duration
+	for {
+		wait, pauses := b.reserve(time.Now())
+		total += wait
+		if err := sleepContext(ctx, wait); err != nil {
+			b.cancel()
+			return total, err
+		}
+		// 等待期间服务端要求暂停时, 归还令牌并重新排队到暂停结束之后
+		if !b.pausedSince(pauses, time.Now()) {
+			break
+		}
+		b.cancel()
+	}
+	b.acquired(total)
+	return total, nil
+}
+
+// pause 暂停发放令牌直到 until
+// 暂停期间不补充令牌, 积累的令牌最多保留一个, 避免暂停结束时突发大量请求
+func (b *tokenBucket) pause(until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !until.After(b.pausedUntil) {
+		return
+	}
+	b.pausedUntil = until
+	b.pauses++
+	if b.rate > 0 {
+		b.advance(time.Now())
+		b.tokens = math.Min(b.tokens, 1)
+		if until.After(b.last) {
+			b.last = until
+		}
+	}
+}
+
+// throttle 记录一次 429 响应并暂停到 until
+func (b *tokenBucket) throttle(until time.Time) {
+	b.pause(until)
+
+	b.mu.Lock()
+	b.stat.Throttled++
+	b.mu.Unlock()
+}
+
+// stats 返回令牌桶的状态
+func (b *tokenBucket) stats(now time.Time) RateClassStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stat := b.stat
+	stat.Tokens = math.Inf(1)
+	if b.rate > 0 {
+		b.advance(now)
+		stat.Tokens = b.tokens
+	}
+	if b.pausedUntil.After(now) {
+		stat.PausedUntil = b.pausedUntil
+	}
+	return stat
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitedClient 创建使用 limiter 的客户端
+func newLimitedClient(t *testing.T, handler http.HandlerFunc, limiter *RateLimiter, opts ...Option) *MemoryClient {
+	t.Helper()
+
+	server := newTestServer(t, handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: PingNever,
+	}, append(opts, WithRateLimiter(limiter))...)
+	require.NoError(t, err)
+	return client
+}
+
+func TestRateClassOf(t *testing.T) {
+	assert.Equal(t, RateRead, rateClassOf("GET", "/v1/memories/m/"))
+	assert.Equal(t, RateRead, rateClassOf("POST", "/v2/memories/search/"))
+	assert.Equal(t, RateRead, rateClassOf("POST", "/v2/memories/"))
+	assert.Equal(t, RateWrite, rateClassOf("POST", "/v1/memories/"))
+	assert.Equal(t, RateWrite, rateClassOf("PUT", "/v1/memories/m/"))
+	assert.Equal(t, RateWrite, rateClassOf("DELETE", "/v1/memories/m/"))
+}
+
+func TestRateLimiterBudgets(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		waits = map[string]int{}
+	)
+	limiter := NewRateLimiter(RateLimitOptions{
+		Read: RateBudget{Rate: 50, Burst: 1},
+		OnWait: func(class RateClass, operation string, wait time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			waits[class.String()+" "+operation]++
+		},
+	})
+	client := newLimitedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}, limiter)
+
+	// 写请求不受读预算限制
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, client.Delete("m"))
+	}
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+
+	start = time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.Get("m")
+		assert.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 55*time.Millisecond)
+
+	stats := limiter.Stats()
+	assert.Equal(t, int64(4), stats.Read.Requests)
+	assert.Equal(t, int64(3), stats.Read.Waited)
+	assert.Greater(t, stats.Read.TotalWait, 50*time.Millisecond)
+	assert.Greater(t, stats.Read.MaxWait, time.Duration(0))
+	assert.Equal(t, int64(4), stats.Write.Requests)
+	assert.Zero(t, stats.Write.Waited)
+	assert.True(t, stats.Write.Tokens > 1e9)
+
+	assert.Equal(t, map[string]int{"write Delete": 4, "read Get": 4}, waits)
+}
+
+func TestRateLimiterAdaptsToHeaders(t *testing.T) {
+	var calls int32
+	limiter := NewRateLimiter(RateLimitOptions{})
+	client := newLimitedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "0.1")
+		}
+		w.Write([]byte(`{}`))
+	}, limiter)
+
+	_, err := client.Get("m")
+	require.NoError(t, err)
+	assert.False(t, limiter.Stats().Read.PausedUntil.IsZero())
+	assert.True(t, limiter.Stats().Write.PausedUntil.IsZero())
+
+	start := time.Now()
+	_, err = client.Get("m")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+}
+
+func TestRateLimiterThrottledRetry(t *testing.T) {
+	var calls int32
+	limiter := NewRateLimiter(RateLimitOptions{})
+	client := newLimitedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("X-RateLimit-Reset", "0.05")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, limiter, WithRetryPolicy(testRetryPolicy()))
+
+	start := time.Now()
+	_, err := client.Get("m")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	stats := limiter.Stats()
+	assert.Equal(t, int64(1), stats.Read.Throttled)
+	assert.Equal(t, int64(2), stats.Read.Requests)
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitOptions{Write: RateBudget{Rate: 1}})
+	client := newLimitedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, limiter)
+
+	require.NoError(t, client.Delete("m"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.DeleteContext(ctx, "m"), context.DeadlineExceeded)
+
+	// 取消的请求归还了预约的令牌, 也不计入等待统计
+	stats := limiter.Stats().Write
+	assert.InDelta(t, 0, stats.Tokens, 0.1)
+	assert.Equal(t, int64(1), stats.Requests)
+	assert.Equal(t, int64(0), stats.Waited)
+	assert.Equal(t, time.Duration(0), stats.TotalWait)
+}
+
+func TestTokenBucketReleasesAtRateAfterPause(t *testing.T) {
+	// 桶是满的, 暂停结束后排队的请求仍然按每 50ms 一个的速率发出
+	bucket := newTokenBucket(20, 5)
+	bucket.throttle(time.Now().Add(50 * time.Millisecond))
+
+	var mu sync.Mutex
+	var released []time.Time
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := bucket.wait(context.Background())
+			assert.NoError(t, err)
+			mu.Lock()
+			released = append(released, time.Now())
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	first, last := released[0], released[0]
+	for _, at := range released {
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	assert.GreaterOrEqual(t, last.Sub(first), 120*time.Millisecond)
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	d, ok := parseRateLimitReset("30", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = parseRateLimitReset("1700000010", now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	_, ok = parseRateLimitReset("soon", now)
+	assert.False(t, ok)
+}
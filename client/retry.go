@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy 定义失败请求的重试策略
+//
+// 对于 429 和 5xx 等可重试的响应, 客户端会按指数退避等待后重试;
+// 如果响应携带 Retry-After 头, 则优先使用服务端给出的等待时间.
+// 非幂等请求 (例如 Add, AddAsync, CreateWebhook, Feedback) 只有在
+// RetryNonIdempotent 为 true 时才会重试, 以避免重复写入.
+type RetryPolicy struct {
+	// MaxAttempts 是包含首次请求在内的最大尝试次数, 小于等于 1 表示不重试
+	MaxAttempts int
+	// BaseBackoff 是第一次重试前的等待时间, 之后每次翻倍
+	BaseBackoff time.Duration
+	// MaxBackoff 是计算出的退避时间上限, 不限制 Retry-After
+	MaxBackoff time.Duration
+	// Jitter 是退避时间中随机部分所占的比例, 取值范围 [0, 1]
+	Jitter float64
+	// RetryStatusCodes 是需要重试的 HTTP 状态码
+	RetryStatusCodes []int
+	// RetryNetworkErrors 为 true 时重试连接被拒绝, 连接重置和超时等网络错误
+	RetryNetworkErrors bool
+	// IsRetryableError 可选, 自定义哪些网络错误需要重试, 设置后覆盖默认判断
+	IsRetryableError func(err error) bool
+	// RetryNonIdempotent 为 true 时同样重试非幂等的请求
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryStatusCodes 是默认重试的 HTTP 状态码
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryPolicy 返回默认的重试策略: 最多尝试 4 次, 退避时间从 500ms 开始,
+// 不超过 30s, 重试 DefaultRetryStatusCodes 和网络错误, 不重试非幂等请求
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:        4,
+		BaseBackoff:        500 * time.Millisecond,
+		MaxBackoff:         30 * time.Second,
+		Jitter:             0.2,
+		RetryStatusCodes:   DefaultRetryStatusCodes,
+		RetryNetworkErrors: true,
+	}
+}
+
+// maxAttempts 返回允许的最大尝试次数
+func (p *RetryPolicy) maxAttempts(idempotent bool) int {
+	if p == nil || p.MaxAttempts <= 1 || (!idempotent && !p.RetryNonIdempotent) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// retryStatus 判断状态码是否需要重试
+func (p *RetryPolicy) retryStatus(code int) bool {
+	for _, c := range p.RetryStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryError 判断请求错误是否需要重试
+func (p *RetryPolicy) retryError(err error) bool {
+	if p.IsRetryableError != nil {
+		return p.IsRetryableError(err)
+	}
+	return p.RetryNetworkErrors && isTransientNetworkError(err)
+}
+
+// backoff 返回第 attempt 次请求失败后 (从 1 开始) 的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseBackoff
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+
+	d := float64(base) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		d -= d * jitter * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// isTransientNetworkError 判断错误是否为可能在重试后恢复的网络错误
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isIdempotent 判断请求是否可以安全重试
+// Search 和 GetAll 虽然使用 POST, 但只读取数据, 因此同样视为幂等
+func isIdempotent(method, path string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return path == "/v2/memories/" || path == "/v2/memories/search/"
+	}
+	return false
+}
+
+// parseRetryAfter 解析 Retry-After 头, 支持秒数和 HTTP 日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// sleepContext 等待 d 或直到 ctx 结束
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
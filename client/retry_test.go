@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRetryPolicy 返回退避时间很短的重试策略, 避免测试变慢
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	var calls int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(types.Memory{ID: "test-id"})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+		Retry:  testRetryPolicy(),
+	})
+	assert.NoError(t, err)
+
+	memory, err := client.Get("test-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", memory.ID)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+		Retry:  testRetryPolicy(),
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get("test-id")
+	assert.Error(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	var calls int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get("test-id")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var calls int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode([]types.Memory{{ID: "test-id"}})
+	})
+	defer server.Close()
+
+	// 默认不重试 Add
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+		Retry:  testRetryPolicy(),
+	})
+	assert.NoError(t, err)
+
+	_, err = client.AddContext(context.Background(), "test memory", types.AddOptions{UserID: "test-user"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 显式开启后重试 Add
+	policy := testRetryPolicy()
+	policy.RetryNonIdempotent = true
+	client, err = NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+		Retry:  policy,
+	})
+	assert.NoError(t, err)
+
+	atomic.StoreInt32(&calls, 0)
+	memories, err := client.AddContext(context.Background(), "test memory", types.AddOptions{UserID: "test-user"})
+	assert.NoError(t, err)
+	assert.Len(t, memories, 1)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRetryAfter(t *testing.T) {
+	var calls int32
+	var first time.Time
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		assert.GreaterOrEqual(t, time.Since(first), time.Second)
+		json.NewEncoder(w).Encode(types.Memory{ID: "test-id"})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+		Retry:  testRetryPolicy(),
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get("test-id")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	var calls int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// 直接断开连接, 模拟网络错误
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		json.NewEncoder(w).Encode(types.Memory{ID: "test-id"})
+	})
+	defer server.Close()
+
+	client, err := NewMemoryClient(ClientOptions{
+		APIKey: "test-key",
+		Host:   server.URL,
+		Retry:  testRetryPolicy(),
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get("test-id")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.backoff(2)
+		assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+		assert.LessOrEqual(t, d, 200*time.Millisecond)
+	}
+}
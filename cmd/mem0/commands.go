@@ -0,0 +1,1192 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/memsync"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// command 是一个子命令
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+// commands 是所有子命令, 按帮助信息中的顺序排列
+var commands = []command{
+	{"add", "<text>", "Add a memory", runAdd},
+	{"get", "<memory-id>", "Get a memory by ID", runGet},
+	{"update", "<memory-id> <text>", "Update the text of a memory", runUpdate},
+	{"delete", "<memory-id>", "Delete a memory", runDelete},
+	{"history", "<memory-id>", "Show the history of a memory", runHistory},
+	{"search", "<query>", "Search memories", runSearch},
+	{"get-all", "", "List memories matching the filters", runGetAll},
+	{"delete-all", "", "Delete all memories of an entity", runDeleteAll},
+	{"batch-update", "<file>", "Update memories from a JSON file ([{\"memoryId\": ..., \"text\": ...}], - for stdin)", runBatchUpdate},
+	{"batch-delete", "[memory-id...]", "Delete memories by ID (or one ID per line with --file)", runBatchDelete},
+	{"users", "", "List users, agents, apps and runs", runUsers},
+	{"delete-user", "<entity-id>", "Delete a user, agent, app or run", runDeleteUser},
+	{"project", "get|update", "Show or update the project settings", runProject},
+	{"webhooks", "list|create|update|delete", "Manage webhooks", runWebhooks},
+	{"feedback", "<memory-id>", "Give feedback on a memory", runFeedback},
+	{"event", "<event-id>", "Get an event by ID", runEvent},
+	{"events", "", "List events", runEvents},
+	{"export", "", "Export memories as JSONL", runExport},
+	{"import", "<file>", "Import memories from JSONL or CSV (- for stdin)", runImport},
+	{"sync", "plan|apply", "Sync memories one way to another project or scope", runSync},
+	{"config", "list|get|set|use", "Manage config profiles", runConfig},
+}
+
+// errUsage 表示参数错误, 已经打印了帮助信息
+var errUsage = errors.New("invalid usage")
+
+// newFlagSet 创建子命令的参数集
+func newFlagSet(name, args, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mem0 %s [flags] %s\n\n%s\n", name, args, summary)
+		var hasFlags bool
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(fs.Output(), "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// parse 解析子命令参数, 并检查位置参数的数量
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if n := fs.NArg(); n < minArgs || (maxArgs >= 0 && n > maxArgs) {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func runAdd(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("add", "<text>", "Add a memory")
+	var entity entityFlags
+	entity.register(fs)
+	metadata := metadataFlag{}
+	fs.Var(metadata, "metadata", "Metadata as key=value, can be repeated")
+	var infer boolPtrFlag
+	fs.Var(&infer, "infer", "Extract memories from the text (default true on the server)")
+	async := fs.Bool("async", false, "Add asynchronously and print the events instead of waiting")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	entity = entity.orDefault(a.profile.entity())
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	options := types.AddOptions{
+		UserID:  entity.userID,
+		AgentID: entity.agentID,
+		AppID:   entity.appID,
+		RunID:   entity.runID,
+		Infer:   infer.value,
+	}
+	if len(metadata) > 0 {
+		options.Metadata = metadata
+	}
+
+	if *async {
+		events, err := mem0.AddAsyncContext(ctx, fs.Arg(0), options)
+		if err != nil {
+			return err
+		}
+		return a.print(events)
+	}
+
+	memories, err := mem0.AddContext(ctx, fs.Arg(0), options)
+	if err != nil {
+		return err
+	}
+	return a.print(memories)
+}
+
+func runGet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("get", "<memory-id>", "Get a memory by ID")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	memory, err := mem0.GetContext(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.print(memory)
+}
+
+func runUpdate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("update", "<memory-id> <text>", "Update the text of a memory")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	memories, err := mem0.UpdateContext(ctx, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	return a.print(memories)
+}
+
+func runDelete(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("delete", "<memory-id>", "Delete a memory")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if err := mem0.DeleteContext(ctx, fs.Arg(0)); err != nil {
+		return err
+	}
+	a.status("Memory deleted successfully")
+	return nil
+}
+
+func runHistory(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("history", "<memory-id>", "Show the history of a memory")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	history, err := mem0.HistoryContext(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.print(history)
+}
+
+func runSearch(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("search", "<query>", "Search memories")
+	var entity entityFlags
+	entity.register(fs)
+	var filters jsonFlag
+	fs.Var(&filters, "filters", "v2 filters as a JSON object, or @file")
+	var categories listFlag
+	fs.Var(&categories, "categories", "Comma-separated categories")
+	topK := fs.Int("top-k", 0, "Maximum number of results")
+	threshold := fs.Float64("threshold", 0, "Minimum similarity score between 0 and 1")
+	rerank := fs.Bool("rerank", false, "Rerank the results")
+	keyword := fs.Bool("keyword", false, "Use keyword search")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if filters == nil {
+		entity = entity.orDefault(a.profile.entity())
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	memories, err := mem0.SearchContext(ctx, fs.Arg(0), &types.QueryOptions{
+		Filters:       entity.filters(filters),
+		Categories:    categories,
+		TopK:          *topK,
+		Threshold:     *threshold,
+		Rerank:        *rerank,
+		KeywordSearch: *keyword,
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(memories)
+}
+
+func runGetAll(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("get-all", "", "List memories matching the filters")
+	var entity entityFlags
+	entity.register(fs)
+	var filters jsonFlag
+	fs.Var(&filters, "filters", "v2 filters as a JSON object, or @file")
+	var categories listFlag
+	fs.Var(&categories, "categories", "Comma-separated categories")
+	page := fs.Int("page", 0, "Page number")
+	pageSize := fs.Int("page-size", 0, "Number of memories per page")
+	all := fs.Bool("all", false, "Fetch every page")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if filters == nil {
+		entity = entity.orDefault(a.profile.entity())
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	options := &types.ListOptions{
+		Filters:    entity.filters(filters),
+		Categories: categories,
+		Page:       *page,
+		PageSize:   *pageSize,
+	}
+
+	if !*all {
+		memories, err := mem0.GetAllContext(ctx, options)
+		if err != nil {
+			return err
+		}
+		return a.print(memories)
+	}
+
+	it := mem0.IterMemories(ctx, options)
+	defer it.Close()
+	var memories []types.Memory
+	for it.Next() {
+		memories = append(memories, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return a.print(memories)
+}
+
+func runDeleteAll(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("delete-all", "", "Delete all memories of an entity")
+	var entity entityFlags
+	entity.register(fs)
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	entity = entity.orDefault(a.profile.entity())
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	err = mem0.DeleteAllContext(ctx, types.DeleteAllOptions{
+		UserID:  entity.userID,
+		AgentID: entity.agentID,
+		AppID:   entity.appID,
+		RunID:   entity.runID,
+	})
+	if err != nil {
+		return err
+	}
+	a.status("Memories deleted successfully")
+	return nil
+}
+
+// batchOptions 注册批量操作的参数
+func batchOptions(fs *flag.FlagSet) *client.BatchOptions {
+	var opts client.BatchOptions
+	fs.IntVar(&opts.BatchSize, "batch-size", client.DefaultBatchSize, "Items per request")
+	fs.IntVar(&opts.Concurrency, "concurrency", 4, "Concurrent requests")
+	return &opts
+}
+
+// printBatchReport 打印批量操作的结果
+func printBatchReport(a *app, report *client.BatchReport, err error) error {
+	if report == nil {
+		return err
+	}
+	for _, item := range report.Failed() {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", item.MemoryID, item.Err)
+	}
+	a.status(fmt.Sprintf("%d succeeded, %d failed, %d retried", len(report.Succeeded()), len(report.Failed()), len(report.Retried())))
+	return err
+}
+
+func runBatchUpdate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("batch-update", "<file>", "Update memories from a JSON file ([{\"memoryId\": ..., \"text\": ...}], - for stdin)")
+	opts := batchOptions(fs)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	var memories []types.MemoryUpdateBody
+	if err := readJSONArg("@"+fs.Arg(0), &memories); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	report, err := mem0.BatchUpdateWithOptions(ctx, memories, *opts)
+	return printBatchReport(a, report, err)
+}
+
+func runBatchDelete(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("batch-delete", "[memory-id...]", "Delete memories by ID (or one ID per line with --file)")
+	opts := batchOptions(fs)
+	file := fs.String("file", "", "Read memory IDs from a file, one per line (- for stdin)")
+	if err := parse(fs, args, 0, -1); err != nil {
+		return err
+	}
+
+	ids := fs.Args()
+	if *file != "" {
+		fileIDs, err := readLines(*file)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, fileIDs...)
+	}
+	if len(ids) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	report, err := mem0.BatchDeleteWithOptions(ctx, ids, *opts)
+	return printBatchReport(a, report, err)
+}
+
+// readLines 读取文件中的非空行, path 为 - 时读取标准输入
+func readLines(path string) ([]string, error) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func runUsers(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("users", "", "List users, agents, apps and runs")
+	all := fs.Bool("all", false, "Fetch every page")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if !*all {
+		users, err := mem0.UsersContext(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(users)
+	}
+
+	it := mem0.IterEntities(ctx)
+	defer it.Close()
+	var users []types.User
+	for it.Next() {
+		users = append(users, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return a.print(users)
+}
+
+func runDeleteUser(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("delete-user", "<entity-id>", "Delete a user, agent, app or run")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if err := mem0.DeleteUserContext(ctx, fs.Arg(0)); err != nil {
+		return err
+	}
+	a.status("Entity deleted successfully")
+	return nil
+}
+
+func runProject(ctx context.Context, a *app, args []string) error {
+	return runSubcommand(ctx, a, "project", args, []command{
+		{"get", "", "Show the project settings", runProjectGet},
+		{"update", "", "Update the project settings", runProjectUpdate},
+	})
+}
+
+func runProjectGet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("project get", "", "Show the project settings")
+	var fields listFlag
+	fs.Var(&fields, "fields", "Comma-separated project fields to return")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	project, err := mem0.GetProjectContext(ctx, types.ProjectOptions{Fields: fields})
+	if err != nil {
+		return err
+	}
+	return a.print(project)
+}
+
+func runProjectUpdate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("project update", "", "Update the project settings")
+	instructions := fs.String("custom-instructions", "", "Custom instructions for memory extraction, or @file")
+	var categories listFlag
+	fs.Var(&categories, "custom-categories", "Comma-separated name=description custom categories")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var payload types.PromptUpdatePayload
+	if *instructions != "" {
+		data, err := readArg(*instructions)
+		if err != nil {
+			return err
+		}
+		payload.CustomInstructions = string(data)
+	}
+	customCategories, err := parseCategories(categories)
+	if err != nil {
+		return err
+	}
+	payload.CustomCategories = customCategories
+	if payload.CustomInstructions == "" && len(payload.CustomCategories) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if err := mem0.UpdateProjectContext(ctx, payload); err != nil {
+		return err
+	}
+	a.status("Project updated successfully")
+	return nil
+}
+
+func runWebhooks(ctx context.Context, a *app, args []string) error {
+	return runSubcommand(ctx, a, "webhooks", args, []command{
+		{"list", "", "List webhooks", runWebhooksList},
+		{"create", "", "Create a webhook", runWebhooksCreate},
+		{"update", "<webhook-id>", "Update a webhook", runWebhooksUpdate},
+		{"delete", "<webhook-id>", "Delete a webhook", runWebhooksDelete},
+	})
+}
+
+func runWebhooksList(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("webhooks list", "", "List webhooks")
+	projectID := fs.String("project", "", "Project ID (defaults to --project-id or the profile's project_id)")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *projectID == "" {
+		*projectID = a.options.ProjectID
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := mem0.GetWebhooksContext(ctx, *projectID)
+	if err != nil {
+		return err
+	}
+	return a.print(webhooks)
+}
+
+// webhookFlags 注册创建和更新 webhook 的参数
+func webhookFlags(fs *flag.FlagSet) (*types.WebhookPayload, *listFlag) {
+	var payload types.WebhookPayload
+	fs.StringVar(&payload.Name, "name", "", "Webhook name")
+	fs.StringVar(&payload.URL, "url", "", "Webhook URL")
+	var events listFlag
+	fs.Var(&events, "events", "Comma-separated event types (memory_add, memory_update, memory_delete)")
+	return &payload, &events
+}
+
+// webhookEvents 将参数转换为事件类型
+func webhookEvents(events listFlag) []types.WebhookEvent {
+	eventTypes := make([]types.WebhookEvent, len(events))
+	for i, event := range events {
+		eventTypes[i] = types.WebhookEvent(event)
+	}
+	return eventTypes
+}
+
+func runWebhooksCreate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("webhooks create", "", "Create a webhook")
+	payload, events := webhookFlags(fs)
+	projectID := fs.String("project", "", "Project ID (required)")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	payload.EventTypes = webhookEvents(*events)
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	webhook, err := mem0.CreateWebhookContext(ctx, *projectID, *payload)
+	if err != nil {
+		return err
+	}
+	return a.print(webhook)
+}
+
+func runWebhooksUpdate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("webhooks update", "<webhook-id>", "Update a webhook")
+	payload, events := webhookFlags(fs)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	payload.WebhookID = fs.Arg(0)
+	payload.EventTypes = webhookEvents(*events)
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if err := mem0.UpdateWebhookContext(ctx, *payload); err != nil {
+		return err
+	}
+	a.status("Webhook updated successfully")
+	return nil
+}
+
+func runWebhooksDelete(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("webhooks delete", "<webhook-id>", "Delete a webhook")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if err := mem0.DeleteWebhookContext(ctx, fs.Arg(0)); err != nil {
+		return err
+	}
+	a.status("Webhook deleted successfully")
+	return nil
+}
+
+func runFeedback(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("feedback", "<memory-id>", "Give feedback on a memory")
+	feedback := fs.String("feedback", "", "POSITIVE, NEGATIVE or VERY_NEGATIVE")
+	reason := fs.String("reason", "", "Reason for the feedback")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	err = mem0.FeedbackContext(ctx, types.FeedbackPayload{
+		MemoryID:       fs.Arg(0),
+		Feedback:       types.Feedback(strings.ToUpper(*feedback)),
+		FeedbackReason: *reason,
+	})
+	if err != nil {
+		return err
+	}
+	a.status("Feedback submitted successfully")
+	return nil
+}
+
+func runEvent(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("event", "<event-id>", "Get an event by ID")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	event, err := mem0.GetEventContext(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.print(event)
+}
+
+func runEvents(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("events", "", "List events")
+	cursor := fs.String("cursor", "", "Next URL of a previous page")
+	all := fs.Bool("all", false, "Fetch every page")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if !*all {
+		events, err := mem0.GetEventsContext(ctx, *cursor)
+		if err != nil {
+			return err
+		}
+		return a.print(events)
+	}
+
+	it := mem0.IterEvents(ctx)
+	defer it.Close()
+	var events []types.Event
+	for it.Next() {
+		events = append(events, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return a.print(events)
+}
+
+func runExport(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("export", "", "Export memories as JSONL")
+	out := fs.String("out", "-", "Output file, - for stdout; interrupted exports to a file resume from <out>.checkpoint")
+	var compress boolPtrFlag
+	fs.Var(&compress, "gzip", "Compress the output (default true when --out ends with .gz)")
+	history := fs.Bool("history", false, "Include the history of each memory")
+	pageSize := fs.Int("page-size", client.DefaultIterPageSize, "Number of memories per page")
+	var users, agents, apps, runs listFlag
+	fs.Var(&users, "user", "Comma-separated user IDs to export, each one a separate scope")
+	fs.Var(&agents, "agent", "Comma-separated agent IDs to export, each one a separate scope")
+	fs.Var(&apps, "app", "Comma-separated app IDs to export, each one a separate scope")
+	fs.Var(&runs, "run", "Comma-separated run IDs to export, each one a separate scope")
+	var filters jsonFlag
+	fs.Var(&filters, "filters", "An additional scope as v2 filters (JSON object or @file)")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var scopes []types.ListOptions
+	for _, entity := range []struct {
+		key string
+		ids listFlag
+	}{{"user_id", users}, {"agent_id", agents}, {"app_id", apps}, {"run_id", runs}} {
+		for _, id := range entity.ids {
+			scopes = append(scopes, types.ListOptions{Filters: map[string]any{entity.key: id}})
+		}
+	}
+	if filters != nil {
+		scopes = append(scopes, types.ListOptions{Filters: filters})
+	}
+	if len(scopes) == 0 {
+		defaults := a.profile.entity()
+		if filters := defaults.filters(nil); filters != nil {
+			scopes = append(scopes, types.ListOptions{Filters: filters})
+		}
+	}
+
+	opts := client.ExportOptions{
+		Scopes:         scopes,
+		PageSize:       *pageSize,
+		IncludeHistory: *history,
+		Gzip:           strings.HasSuffix(*out, ".gz"),
+	}
+	if compress.value != nil {
+		opts.Gzip = *compress.value
+	}
+
+	mem0, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		stats, err := mem0.Export(ctx, os.Stdout, opts)
+		fmt.Fprintf(os.Stderr, "Exported %d memories\n", stats.Records)
+		return err
+	}
+
+	stats, err := mem0.ExportFile(ctx, *out, opts)
+	if stats.Resumed {
+		fmt.Fprintf(os.Stderr, "Resumed the export from %s.checkpoint\n", *out)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Exported %d memories before the error, run the same command again to resume\n", stats.Records)
+		return err
+	}
+	a.status(fmt.Sprintf("Exported %d memories to %s", stats.Records, *out))
+	return nil
+}
+
+func runImport(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("import", "<file>", "Import memories from JSONL or CSV (- for stdin)")
+	format := fs.String("format", "", "jsonl or csv (default from the file extension, else jsonl)")
+	noInfer := fs.Bool("no-infer", false, "Store the text verbatim instead of extracting memories from it")
+	users, agents, apps, runs := mapFlag{}, mapFlag{}, mapFlag{}, mapFlag{}
+	fs.Var(users, "map-user", "Rename a user ID as old=new, can be repeated; * matches every other ID")
+	fs.Var(agents, "map-agent", "Rename an agent ID as old=new, can be repeated")
+	fs.Var(apps, "map-app", "Rename an app ID as old=new, can be repeated")
+	fs.Var(runs, "map-run", "Rename a run ID as old=new, can be repeated")
+	dedupe := fs.Bool("dedupe", false, "Skip records whose hash matches a memory that already exists")
+	dryRun := fs.Bool("dry-run", false, "Print what would be created without adding anything; needs no API key unless --dedupe is set")
+	workers := fs.Int("workers", 4, "Concurrent Add requests")
+	rate := fs.Float64("rate", 0, "Maximum Add requests per second, 0 for no limit")
+	checkpoint := fs.String("checkpoint", "", "File recording imported lines, so an interrupted import can be resumed")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	importFormat := client.ImportJSONL
+	switch {
+	case *format == "csv", *format == "" && strings.HasSuffix(strings.TrimSuffix(path, ".gz"), ".csv"):
+		importFormat = client.ImportCSV
+	case *format == "", *format == "jsonl":
+	default:
+		return fmt.Errorf("unknown import format %q", *format)
+	}
+
+	r, err := openInput(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var mem0 *client.MemoryClient
+	if *dryRun && !*dedupe {
+		// 不查重的 dry run 只在本地解析和校验记录, 不发送请求, 因此不需要 API key, 也不 ping
+		mem0, err = client.NewMemoryClient(client.ClientOptions{APIKey: "dry-run", Host: a.options.Host, PingMode: client.PingNever})
+	} else {
+		mem0, err = a.client()
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := client.ImportOptions{
+		Format:         importFormat,
+		SkipInference:  *noInfer,
+		Remap:          client.EntityRemap{Users: client.IDMap(users), Agents: client.IDMap(agents), Apps: client.IDMap(apps), Runs: client.IDMap(runs)},
+		DedupeExisting: *dedupe,
+		DryRun:         *dryRun,
+		Ingest:         client.IngestOptions{Workers: *workers, RateLimit: *rate},
+	}
+	if *checkpoint != "" && !*dryRun {
+		cp, err := client.OpenFileCheckpoint(*checkpoint)
+		if err != nil {
+			return err
+		}
+		defer cp.Close()
+		opts.Ingest.Checkpoint = cp
+	}
+
+	type importLine struct {
+		Line    int    `json:"line"`
+		Action  string `json:"action"`
+		Text    string `json:"text"`
+		UserID  string `json:"user_id,omitempty"`
+		AgentID string `json:"agent_id,omitempty"`
+		AppID   string `json:"app_id,omitempty"`
+		RunID   string `json:"run_id,omitempty"`
+		Error   string `json:"error,omitempty"`
+	}
+	var lines []importLine
+	opts.OnResult = func(result client.ImportResult) {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: %v\n", result.Record.Line, result.Err)
+		}
+		if *dryRun {
+			record := result.Record
+			line := importLine{Line: record.Line, Action: result.Action.String(), Text: record.Text,
+				UserID: record.UserID, AgentID: record.AgentID, AppID: record.AppID, RunID: record.RunID}
+			if result.Err != nil {
+				line.Error = result.Err.Error()
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	report, err := mem0.Import(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		if err := a.print(lines); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Dry run: %d would be created, %d duplicates, %d invalid\n", report.WouldCreate, report.Duplicates, report.Failed)
+		return nil
+	}
+	a.status(fmt.Sprintf("%d created, %d duplicates, %d already imported, %d failed", report.Created, report.Duplicates, report.Skipped, report.Failed))
+	if report.Failed > 0 {
+		return fmt.Errorf("%d records failed to import", report.Failed)
+	}
+	return nil
+}
+
+// openInput 打开输入文件, - 表示标准输入, 以 .gz 结尾时解压
+func openInput(path string) (io.ReadCloser, error) {
+	var f io.ReadCloser = os.Stdin
+	if path != "-" {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return nil, err
+		}
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+
+	zr, err := gzip.NewReader(f)
+	if err == io.EOF {
+		// Export 没有导出任何内存时文件为空
+		return struct {
+			io.Reader
+			io.Closer
+		}{strings.NewReader(""), f}, nil
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{zr, f}, nil
+}
+
+func runSync(ctx context.Context, a *app, args []string) error {
+	return runSubcommand(ctx, a, "sync", args, []command{
+		{"plan", "", "Compare the memories with the target and show or save the differences", runSyncPlan},
+		{"apply", "<plan-file>", "Apply a plan saved by sync plan --out", runSyncApply},
+	})
+}
+
+// syncTarget 是同步的目标项目, Profile 为空表示执行命令时选择的 profile
+type syncTarget struct {
+	Profile     string `json:"profile,omitempty"`
+	ProjectID   string `json:"project_id,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+}
+
+// syncPlanFile 是 sync plan --out 保存的文件, 记录目标以便 sync apply 写入同一个项目
+type syncPlanFile struct {
+	To syncTarget `json:"to"`
+	*memsync.Plan
+}
+
+// syncChange 是输出的一条差异
+type syncChange struct {
+	Kind     memsync.ChangeKind `json:"kind"`
+	Key      string             `json:"key"`
+	Source   string             `json:"source,omitempty"`
+	Target   string             `json:"target,omitempty"`
+	Text     bool               `json:"text_changed,omitempty"`
+	Metadata bool               `json:"metadata_changed,omitempty"`
+	Skipped  bool               `json:"skipped,omitempty"`
+}
+
+func runSyncPlan(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("sync plan", "", "Compare the memories with the target and show or save the differences")
+	var source, target entityFlags
+	source.register(fs)
+	fs.StringVar(&target.userID, "to-user", "", "Target user ID (default --user)")
+	fs.StringVar(&target.agentID, "to-agent", "", "Target agent ID (default --agent)")
+	fs.StringVar(&target.appID, "to-app", "", "Target app ID (default --app)")
+	fs.StringVar(&target.runID, "to-run", "", "Target run ID (default --run)")
+	var filters jsonFlag
+	fs.Var(&filters, "filters", "Additional v2 filters for both sides (JSON object or @file)")
+	var to syncTarget
+	fs.StringVar(&to.Profile, "to-profile", "", "Config profile of the target (default the current profile)")
+	fs.StringVar(&to.ProjectID, "to-project-id", "", "Target project ID")
+	fs.StringVar(&to.ProjectName, "to-project-name", "", "Target project name")
+	matchKey := fs.String("match-key", "", "Metadata key identifying the same memory on both sides (default match by text)")
+	var ignore listFlag
+	fs.Var(&ignore, "ignore-metadata", "Comma-separated metadata keys ignored when comparing")
+	conflict := fs.String("conflict", "source", "What to do when a memory differs: source, target or newer")
+	out := fs.String("out", "", "Save the plan to a file for sync apply instead of printing the differences")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	policy, err := memsync.ParseConflictPolicy(*conflict)
+	if err != nil {
+		return err
+	}
+	if filters == nil {
+		source = source.orDefault(a.profile.entity())
+	}
+	target = target.orDefault(source)
+
+	from, err := a.client()
+	if err != nil {
+		return err
+	}
+	dest, err := a.clientFor(ctx, to.Profile, to.ProjectID, to.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	scope := func(e entityFlags) memsync.Scope {
+		return memsync.Scope{UserID: e.userID, AgentID: e.agentID, AppID: e.appID, RunID: e.runID, Filters: filters}
+	}
+	plan, err := memsync.Diff(ctx,
+		memsync.Side{Client: from, Scope: scope(source)},
+		memsync.Side{Client: dest, Scope: scope(target)},
+		memsync.Options{MatchKey: *matchKey, IgnoreMetadata: ignore, Conflict: policy})
+	if err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("%d to add, %d to change, %d only in the target", plan.Count(memsync.Added), plan.Count(memsync.Changed), plan.Count(memsync.Removed))
+
+	if *out != "" {
+		data, err := json.MarshalIndent(syncPlanFile{To: to, Plan: plan}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*out, append(data, '\n'), 0o600); err != nil {
+			return err
+		}
+		a.status(fmt.Sprintf("%s; saved the plan to %s", summary, *out))
+		return nil
+	}
+
+	changes := make([]syncChange, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		c := syncChange{Kind: change.Kind, Key: change.Key, Text: change.TextChanged, Metadata: change.MetadataChanged, Skipped: change.Skipped}
+		if change.Source != nil {
+			c.Source = change.Source.Memory
+		}
+		if change.Target != nil {
+			c.Target = change.Target.Memory
+		}
+		changes = append(changes, c)
+	}
+	if err := a.print(changes); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, summary)
+	return nil
+}
+
+func runSyncApply(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("sync apply", "<plan-file>", "Apply a plan saved by sync plan --out")
+	prune := fs.Bool("prune", false, "Also delete memories that exist only in the target")
+	infer := fs.Bool("infer", false, "Extract memories from the source text instead of copying it verbatim")
+	workers := fs.Int("workers", 4, "Concurrent Add requests")
+	batch := batchOptions(fs)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	data, err := readArg("@" + fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var file syncPlanFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("invalid plan file %s: %w", fs.Arg(0), err)
+	}
+	if file.Plan == nil {
+		return fmt.Errorf("invalid plan file %s: no changes", fs.Arg(0))
+	}
+
+	dest, err := a.clientFor(ctx, file.To.Profile, file.To.ProjectID, file.To.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	report, err := memsync.Apply(ctx, dest, file.Plan, memsync.ApplyOptions{Prune: *prune, Infer: *infer, Workers: *workers, Batch: *batch})
+	if report == nil {
+		return err
+	}
+	for _, result := range report.Failed() {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", result.Change.Kind, result.Change.Key, result.Err)
+	}
+	msg := fmt.Sprintf("%d added, %d changed, %d removed, %d failed",
+		report.Applied(memsync.Added), report.Applied(memsync.Changed), report.Applied(memsync.Removed), len(report.Failed()))
+	if n := file.Plan.Count(memsync.Removed); n > 0 && !*prune {
+		msg += fmt.Sprintf("; %d memories only in the target were kept, use --prune to delete them", n)
+	}
+	a.status(msg)
+	return err
+}
+
+func runConfig(ctx context.Context, a *app, args []string) error {
+	return runSubcommand(ctx, a, "config", args, []command{
+		{"list", "", "List profiles", runConfigList},
+		{"get", "<key>", "Print a setting of the profile", runConfigGet},
+		{"set", "<key> <value>", "Change a setting of the profile, creating it if needed", runConfigSet},
+		{"use", "<profile>", "Make a profile the default", runConfigUse},
+	})
+}
+
+func runConfigList(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("config list", "", "List profiles")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	type profileInfo struct {
+		Name    string `json:"name"`
+		Current bool   `json:"current"`
+		*profile
+	}
+	profiles := make([]profileInfo, 0, len(a.config.Profiles))
+	for _, name := range a.config.names() {
+		p := *a.config.Profiles[name]
+		if p.APIKey != "" {
+			p.APIKey = maskSecret(p.APIKey)
+		}
+		profiles = append(profiles, profileInfo{Name: name, Current: name == a.profileName, profile: &p})
+	}
+	return a.print(profiles)
+}
+
+func runConfigGet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("config get", "<key>", "Print a setting of the profile")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	if a.profileErr != nil {
+		return a.profileErr
+	}
+	value, err := a.profile.field(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Println(*value)
+	return nil
+}
+
+func runConfigSet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("config set", "<key> <value>", "Change a setting of the profile, creating it if needed")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	p, _ := a.config.profile(a.profileName, true)
+	value, err := p.field(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	*value = fs.Arg(1)
+	if err := a.config.save(a.configPath); err != nil {
+		return err
+	}
+	a.status(fmt.Sprintf("Set %s in profile %q", fs.Arg(0), a.profileName))
+	return nil
+}
+
+func runConfigUse(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("config use", "<profile>", "Make a profile the default")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+	if _, ok := a.config.profile(name, false); !ok {
+		return fmt.Errorf("profile %q not found in %s", name, a.configPath)
+	}
+	a.config.CurrentProfile = name
+	if err := a.config.save(a.configPath); err != nil {
+		return err
+	}
+	a.status(fmt.Sprintf("Using profile %q", name))
+	return nil
+}
+
+// runSubcommand 执行 group 下的子命令, 例如 "project get"
+func runSubcommand(ctx context.Context, a *app, group string, args []string, subcommands []command) error {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "Usage: mem0 %s <command> [flags]\n\nCommands:\n", group)
+		printCommands(subcommands)
+	}
+
+	if len(args) == 0 {
+		usage()
+		return errUsage
+	}
+	for _, cmd := range subcommands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, a, args[1:])
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return flag.ErrHelp
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command: %s %s\n\n", group, args[0])
+	usage()
+	return errUsage
+}
+
+// printCommands 打印命令列表
+func printCommands(cmds []command) {
+	for _, cmd := range cmds {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+}
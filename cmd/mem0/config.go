@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile 是没有指定 profile 且配置文件中没有 current_profile 时使用的 profile
+const defaultProfile = "default"
+
+// profile 是配置文件中的一组连接参数和默认实体
+type profile struct {
+	Host   string `yaml:"host,omitempty" json:"host,omitempty"`
+	APIKey string `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	// APIKeyCommand 是输出 API key 的 shell 命令, 例如从密码管理器中读取, 只在需要时执行
+	APIKeyCommand string `yaml:"api_key_command,omitempty" json:"api_key_command,omitempty"`
+	OrgName       string `yaml:"org_name,omitempty" json:"org_name,omitempty"`
+	ProjectName   string `yaml:"project_name,omitempty" json:"project_name,omitempty"`
+	OrgID         string `yaml:"org_id,omitempty" json:"org_id,omitempty"`
+	ProjectID     string `yaml:"project_id,omitempty" json:"project_id,omitempty"`
+	UserID        string `yaml:"user_id,omitempty" json:"user_id,omitempty"`
+	AgentID       string `yaml:"agent_id,omitempty" json:"agent_id,omitempty"`
+	AppID         string `yaml:"app_id,omitempty" json:"app_id,omitempty"`
+	RunID         string `yaml:"run_id,omitempty" json:"run_id,omitempty"`
+}
+
+// profileKeys 是 config set/get 支持的键
+var profileKeys = []string{
+	"host", "api_key", "api_key_command",
+	"org_name", "project_name", "org_id", "project_id",
+	"user_id", "agent_id", "app_id", "run_id",
+}
+
+// field 返回键对应的字段
+func (p *profile) field(key string) (*string, error) {
+	switch key {
+	case "host":
+		return &p.Host, nil
+	case "api_key":
+		return &p.APIKey, nil
+	case "api_key_command":
+		return &p.APIKeyCommand, nil
+	case "org_name":
+		return &p.OrgName, nil
+	case "project_name":
+		return &p.ProjectName, nil
+	case "org_id":
+		return &p.OrgID, nil
+	case "project_id":
+		return &p.ProjectID, nil
+	case "user_id":
+		return &p.UserID, nil
+	case "agent_id":
+		return &p.AgentID, nil
+	case "app_id":
+		return &p.AppID, nil
+	case "run_id":
+		return &p.RunID, nil
+	}
+	return nil, fmt.Errorf("unknown config key %q (want one of %s)", key, strings.Join(profileKeys, ", "))
+}
+
+// entity 返回 profile 中的默认实体
+func (p *profile) entity() entityFlags {
+	return entityFlags{userID: p.UserID, agentID: p.AgentID, appID: p.AppID, runID: p.RunID}
+}
+
+// hasAPIKey 报告 profile 是否配置了 api_key 或 api_key_command
+func (p *profile) hasAPIKey() bool {
+	return p.APIKey != "" || p.APIKeyCommand != ""
+}
+
+// apiKey 返回 API key, 优先使用 api_key, 其次执行 api_key_command
+func (p *profile) apiKey(ctx context.Context) (string, error) {
+	if p.APIKey != "" || p.APIKeyCommand == "" {
+		return p.APIKey, nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", p.APIKeyCommand)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.APIKeyCommand)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("api_key_command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", errors.New("api_key_command printed an empty API key")
+	}
+	return key, nil
+}
+
+// config 是 CLI 的配置文件
+type config struct {
+	CurrentProfile string              `yaml:"current_profile,omitempty" json:"current_profile,omitempty"`
+	Profiles       map[string]*profile `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+}
+
+// configPath 返回配置文件的路径, 可以用 MEM0_CONFIG 环境变量覆盖
+func configPath() (string, error) {
+	if path := os.Getenv("MEM0_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mem0", "config.yaml"), nil
+}
+
+// loadConfig 读取配置文件, 文件不存在时返回空配置
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// save 写入配置文件, 文件中可能有 API key, 因此只有当前用户可以读取
+func (c *config) save(path string) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// profileName 返回要使用的 profile 名称: name, MEM0_PROFILE, current_profile, 最后是 default
+func (c *config) profileName(name string) string {
+	switch {
+	case name != "":
+		return name
+	case os.Getenv("MEM0_PROFILE") != "":
+		return os.Getenv("MEM0_PROFILE")
+	case c.CurrentProfile != "":
+		return c.CurrentProfile
+	}
+	return defaultProfile
+}
+
+// profile 返回名为 name 的 profile, create 为 true 时不存在则创建
+func (c *config) profile(name string, create bool) (*profile, bool) {
+	if p, ok := c.Profiles[name]; ok && p != nil {
+		return p, true
+	}
+	if !create {
+		return &profile{}, false
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*profile)
+	}
+	p := &profile{}
+	c.Profiles[name] = p
+	return p, true
+}
+
+// names 返回排序后的 profile 名称
+func (c *config) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maskSecret 只显示 API key 的最后 4 个字符
+func maskSecret(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", 8) + s[len(s)-4:]
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mem0", "config.yaml")
+
+	cfg, err := loadConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Profiles)
+
+	p, _ := cfg.profile("staging", true)
+	host, err := p.field("host")
+	require.NoError(t, err)
+	*host = "https://staging.example.com"
+	p.UserID = "alice"
+	cfg.CurrentProfile = "staging"
+	require.NoError(t, cfg.save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	loaded, err := loadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"staging"}, loaded.names())
+	assert.Equal(t, "staging", loaded.profileName(""))
+	assert.Equal(t, "prod", loaded.profileName("prod"))
+
+	staging, ok := loaded.profile("staging", false)
+	require.True(t, ok)
+	assert.Equal(t, "https://staging.example.com", staging.Host)
+	assert.Equal(t, entityFlags{userID: "alice"}, staging.entity())
+
+	_, ok = loaded.profile("prod", false)
+	assert.False(t, ok)
+
+	_, err = staging.field("bogus")
+	assert.Error(t, err)
+}
+
+func TestProfileAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := (&profile{APIKey: "sk-direct", APIKeyCommand: "exit 1"}).apiKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-direct", key)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("api_key_command tests use sh")
+	}
+
+	key, err = (&profile{APIKeyCommand: "echo sk-from-command"}).apiKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-from-command", key)
+
+	_, err = (&profile{APIKeyCommand: "echo oops >&2; exit 1"}).apiKey(ctx)
+	assert.ErrorContains(t, err, "oops")
+}
+
+func TestEntityDefaults(t *testing.T) {
+	defaults := entityFlags{userID: "alice"}
+	assert.Equal(t, defaults, entityFlags{}.orDefault(defaults))
+	assert.Equal(t, entityFlags{agentID: "bot"}, entityFlags{agentID: "bot"}.orDefault(defaults))
+}
+
+func TestEnvAPIKey(t *testing.T) {
+	t.Setenv("MEM0_API_KEY", "sk-staging")
+
+	// profile 带有 API key 时不使用环境变量中的 key
+	assert.Empty(t, envAPIKey(&profile{Host: "https://prod.example.com", APIKey: "sk-prod"}))
+	assert.Empty(t, envAPIKey(&profile{APIKeyCommand: "echo sk-prod"}))
+	assert.Equal(t, "sk-staging", envAPIKey(&profile{Host: "https://prod.example.com"}))
+}
+
+func TestProfileKeyOverridesEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := &config{CurrentProfile: "prod", Profiles: map[string]*profile{
+		"prod":    {Host: "https://prod.example.com", APIKey: "sk-prod"},
+		"staging": {Host: "https://staging.example.com", APIKey: "sk-other"},
+	}}
+	require.NoError(t, cfg.save(path))
+	t.Setenv("MEM0_CONFIG", path)
+	t.Setenv("MEM0_API_KEY", "sk-staging")
+
+	tests := []struct {
+		name        string
+		flag        string
+		env         string
+		wantProfile string
+	}{
+		{name: "flag", flag: "staging", wantProfile: "staging"},
+		{name: "MEM0_PROFILE", env: "staging", wantProfile: "staging"},
+		{name: "current_profile", wantProfile: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(old string) { profileFlag = old }(profileFlag)
+			profileFlag = tt.flag
+			t.Setenv("MEM0_PROFILE", tt.env)
+
+			a, err := newApp()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProfile, a.profileName)
+			assert.Empty(t, a.options.APIKey)
+			assert.Equal(t, cfg.Profiles[tt.wantProfile].APIKey, a.profile.APIKey)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// entityFlags 是内存所属实体的 ID
+type entityFlags struct {
+	userID  string
+	agentID string
+	appID   string
+	runID   string
+}
+
+// register 注册 --user, --agent, --app 和 --run 参数
+func (e *entityFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&e.userID, "user", "", "User ID")
+	fs.StringVar(&e.agentID, "agent", "", "Agent ID")
+	fs.StringVar(&e.appID, "app", "", "App ID")
+	fs.StringVar(&e.runID, "run", "", "Run ID")
+}
+
+// orDefault 在没有指定任何实体时使用 profile 中的默认实体
+func (e entityFlags) orDefault(defaults entityFlags) entityFlags {
+	if e == (entityFlags{}) {
+		return defaults
+	}
+	return e
+}
+
+// filters 将实体 ID 合并到 v2 过滤条件中, 已经在 filters 中指定的字段不会被覆盖
+func (e *entityFlags) filters(filters map[string]any) map[string]any {
+	if filters == nil {
+		filters = make(map[string]any)
+	}
+	for key, value := range map[string]string{
+		"user_id":  e.userID,
+		"agent_id": e.agentID,
+		"app_id":   e.appID,
+		"run_id":   e.runID,
+	} {
+		if _, ok := filters[key]; !ok && value != "" {
+			filters[key] = value
+		}
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	return filters
+}
+
+// metadataFlag 是可以重复指定的 key=value 参数, 值会尽量解析为 JSON
+type metadataFlag map[string]any
+
+func (m metadataFlag) String() string {
+	data, _ := json.Marshal(map[string]any(m))
+	return string(data)
+}
+
+func (m metadataFlag) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		v = value
+	}
+	m[key] = v
+	return nil
+}
+
+// mapFlag 是可以重复指定的 old=new 参数
+type mapFlag map[string]string
+
+func (m mapFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m mapFlag) Set(s string) error {
+	from, to, ok := strings.Cut(s, "=")
+	if !ok || from == "" || to == "" {
+		return fmt.Errorf("expected old=new, got %q", s)
+	}
+	m[from] = to
+	return nil
+}
+
+// jsonFlag 是 JSON 对象参数, 以 @ 开头时从文件读取
+type jsonFlag map[string]any
+
+func (j *jsonFlag) String() string {
+	if *j == nil {
+		return ""
+	}
+	data, _ := json.Marshal(map[string]any(*j))
+	return string(data)
+}
+
+func (j *jsonFlag) Set(s string) error {
+	data, err := readArg(s)
+	if err != nil {
+		return err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("invalid JSON object: %w", err)
+	}
+	*j = m
+	return nil
+}
+
+// listFlag 是逗号分隔的列表参数
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// boolPtrFlag 是未指定时为 nil 的布尔参数
+type boolPtrFlag struct {
+	value *bool
+}
+
+func (b *boolPtrFlag) String() string {
+	if b.value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b.value)
+}
+
+func (b *boolPtrFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	b.value = &v
+	return nil
+}
+
+func (b *boolPtrFlag) IsBoolFlag() bool {
+	return true
+}
+
+// readArg 读取参数内容, "@path" 读取文件, "@-" 读取标准输入, 其他值原样返回
+func readArg(s string) ([]byte, error) {
+	switch {
+	case s == "@-":
+		return io.ReadAll(os.Stdin)
+	case strings.HasPrefix(s, "@"):
+		return os.ReadFile(s[1:])
+	}
+	return []byte(s), nil
+}
+
+// readJSONArg 读取参数内容并解码到 v
+func readJSONArg(s string, v any) error {
+	data, err := readArg(s)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+// parseCategories 解析 name=description 形式的自定义分类
+func parseCategories(items []string) ([]types.CustomCategory, error) {
+	categories := make([]types.CustomCategory, len(items))
+	for i, item := range items {
+		name, description, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=description, got %q", item)
+		}
+		categories[i] = types.CustomCategory{CategoryName: name, CategoryDescription: description}
+	}
+	return categories, nil
+}
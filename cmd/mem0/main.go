@@ -1,121 +1,238 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/bytectlgo/mem0-go/client"
-	"github.com/bytectlgo/mem0-go/types"
 )
 
+const defaultHost = "https://api.mem0.ai"
+
 var (
 	host             string
 	organizationName string
 	projectName      string
 	organizationID   string
 	projectID        string
+	output           string
+	fields           listFlag
+	profileFlag      string
 )
 
 func init() {
-	flag.StringVar(&host, "host", "https://api.mem0.ai", "Mem0 API host")
+	flag.StringVar(&profileFlag, "profile", "", "Config profile (default $MEM0_PROFILE, then current_profile in the config file)")
+	flag.StringVar(&host, "host", defaultHost, "Mem0 API host")
 	flag.StringVar(&organizationName, "org-name", "", "Organization name")
 	flag.StringVar(&projectName, "project-name", "", "Project name")
 	flag.StringVar(&organizationID, "org-id", "", "Organization ID")
 	flag.StringVar(&projectID, "project-id", "", "Project ID")
+	flag.StringVar(&output, "output", "json", "Output format: json, jsonl, yaml, table or template=<Go template> (template=@file reads the template from a file)")
+	flag.Var(&fields, "fields", "Comma-separated fields to output, e.g. id,memory,metadata.source")
 }
 
-func main() {
-	flag.Parse()
+// app 保存命令共用的状态, 客户端在第一次使用时创建
+type app struct {
+	options client.ClientOptions
+	mem0    *client.MemoryClient
+	printer *printer
+
+	configPath  string
+	config      *config
+	profileName string
+	profile     *profile
+	// profileErr 是选择的 profile 不存在时的错误, 只在需要客户端时返回, config 命令不受影响
+	profileErr error
+}
 
-	apiKey := os.Getenv("MEM0_API_KEY")
-	if apiKey == "" {
-		log.Fatal("MEM0_API_KEY environment variable is required")
+// newApp 按命令行参数, 环境变量, profile, 默认值的顺序确定客户端参数
+func newApp() (*app, error) {
+	p, err := newPrinter(output, fields)
+	if err != nil {
+		return nil, err
 	}
 
-	// 创建客户端
-	mem0, err := client.NewMemoryClient(client.ClientOptions{
-		APIKey:           apiKey,
-		Host:             host,
-		OrganizationName: organizationName,
-		ProjectName:      projectName,
-		OrganizationID:   organizationID,
-		ProjectID:        projectID,
-	})
+	path, err := configPath()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
 	}
 
-	// 根据命令行参数执行相应操作
-	args := flag.Args()
-	if len(args) == 0 {
-		fmt.Println("Usage: mem0 <command> [options]")
-		fmt.Println("Commands:")
-		fmt.Println("  add <text> - Add a new memory")
-		fmt.Println("  get <id> - Get a memory by ID")
-		fmt.Println("  search <query> - Search memories")
-		fmt.Println("  delete <id> - Delete a memory")
-		return
+	a := &app{printer: p, configPath: path, config: cfg}
+	a.profileName = cfg.profileName(profileFlag)
+	var ok bool
+	if a.profile, ok = cfg.profile(a.profileName, false); !ok && a.profileName != defaultProfile {
+		a.profileErr = fmt.Errorf("profile %q not found in %s", a.profileName, path)
 	}
 
-	switch args[0] {
-	case "add":
-		if len(args) < 2 {
-			log.Fatal("Text is required for add command")
-		}
-		memories, err := mem0.Add(args[1], types.MemoryOptions{
-			UserID: "default_user",
-		})
-		if err != nil {
-			log.Fatal(err)
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	value := func(name, flagValue, profileValue, defaultValue string) string {
+		switch {
+		case set[name]:
+			return flagValue
+		case profileValue != "":
+			return profileValue
 		}
-		printJSON(memories)
+		return defaultValue
+	}
 
-	case "get":
-		if len(args) < 2 {
-			log.Fatal("ID is required for get command")
-		}
-		memory, err := mem0.Get(args[1])
+	a.options = client.ClientOptions{
+		APIKey:           envAPIKey(a.profile),
+		Host:             value("host", host, a.profile.Host, defaultHost),
+		OrganizationName: value("org-name", organizationName, a.profile.OrgName, ""),
+		ProjectName:      value("project-name", projectName, a.profile.ProjectName, ""),
+		OrganizationID:   value("org-id", organizationID, a.profile.OrgID, ""),
+		ProjectID:        value("project-id", projectID, a.profile.ProjectID, ""),
+	}
+	return a, nil
+}
+
+// envAPIKey 返回 MEM0_API_KEY. 选中的 profile 带有 API key 时返回空, 使 profile 的 key 优先,
+// 避免把环境变量中其他环境的 key 发送到这个 profile 的 host
+func envAPIKey(p *profile) string {
+	if p.hasAPIKey() {
+		return ""
+	}
+	return os.Getenv("MEM0_API_KEY")
+}
+
+// client 返回客户端, API key 来自 MEM0_API_KEY 环境变量或 profile, 选中的 profile 带有 key 时优先
+func (a *app) client() (*client.MemoryClient, error) {
+	if a.mem0 != nil {
+		return a.mem0, nil
+	}
+	if a.profileErr != nil {
+		return nil, a.profileErr
+	}
+	if a.options.APIKey == "" {
+		key, err := a.profile.apiKey(context.Background())
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		printJSON(memory)
+		a.options.APIKey = key
+	}
+	if a.options.APIKey == "" {
+		return nil, errors.New("an API key is required: set MEM0_API_KEY or api_key in the config profile")
+	}
+
+	mem0, err := client.NewMemoryClient(a.options)
+	if err != nil {
+		return nil, err
+	}
+	a.mem0 = mem0
+	return mem0, nil
+}
 
-	case "search":
-		if len(args) < 2 {
-			log.Fatal("Query is required for search command")
+// clientFor 返回另一个 profile 或项目的客户端, profileName 为空时使用当前的 profile
+// projectID 和 projectName 不为空时覆盖 profile 中的项目
+func (a *app) clientFor(ctx context.Context, profileName, projectID, projectName string) (*client.MemoryClient, error) {
+	if (profileName == "" || profileName == a.profileName) && projectID == "" && projectName == "" {
+		return a.client()
+	}
+
+	var options client.ClientOptions
+	if profileName == "" || profileName == a.profileName {
+		if _, err := a.client(); err != nil {
+			return nil, err
+		}
+		options = a.options
+	} else {
+		p, ok := a.config.profile(profileName, false)
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in %s", profileName, a.configPath)
 		}
-		results, err := mem0.Search(args[1], &types.SearchOptions{
-			MemoryOptions: types.MemoryOptions{
-				UserID: "default_user",
-			},
-		})
+		key, err := p.apiKey(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		printJSON(results)
-
-	case "delete":
-		if len(args) < 2 {
-			log.Fatal("ID is required for delete command")
+		if key == "" {
+			return nil, fmt.Errorf("profile %q has no api_key or api_key_command", profileName)
 		}
-		err := mem0.Delete(args[1])
-		if err != nil {
-			log.Fatal(err)
+		options = client.ClientOptions{
+			APIKey:           key,
+			Host:             p.Host,
+			OrganizationName: p.OrgName,
+			ProjectName:      p.ProjectName,
+			OrganizationID:   p.OrgID,
+			ProjectID:        p.ProjectID,
 		}
-		fmt.Println("Memory deleted successfully")
+		if options.Host == "" {
+			options.Host = defaultHost
+		}
+	}
 
-	default:
-		log.Fatalf("Unknown command: %s", args[0])
+	if projectID != "" {
+		options.ProjectID = projectID
 	}
+	if projectName != "" {
+		options.ProjectName = projectName
+	}
+	return client.NewMemoryClient(options)
+}
+
+// print 将结果按 --output 指定的格式输出到标准输出
+func (a *app) print(v interface{}) error {
+	return a.printer.print(os.Stdout, v)
+}
+
+// status 输出没有返回值的命令的结果
+func (a *app) status(msg string) {
+	fmt.Println(msg)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: mem0 [global flags] <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	printCommands(commands)
+	fmt.Fprintln(os.Stderr, "\nGlobal flags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "\nRun 'mem0 <command> -h' for the flags of a command.")
 }
 
-func printJSON(v interface{}) {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(v); err != nil {
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	a, err := newApp()
+	if err != nil {
 		log.Fatal(err)
 	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(context.Background(), a, args[1:])
+		switch {
+		case err == nil:
+		case errors.Is(err, flag.ErrHelp):
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		default:
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if args[0] == "help" {
+		usage()
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+	usage()
+	os.Exit(2)
 }
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// zeroTime 是 time.Time 零值的 JSON 格式, 表示服务端没有返回该时间
+const zeroTime = "0001-01-01T00:00:00Z"
+
+// defaultColumns 是 table 格式下内存类结果的默认列
+var defaultColumns = []string{"id", "memory", "score", "categories", "created_at"}
+
+// printer 按 --output 和 --fields 输出命令的结果
+type printer struct {
+	format string
+	fields []string
+	tmpl   *template.Template
+}
+
+// newPrinter 解析输出格式: json, jsonl, yaml, table 或 template=<Go 模板> (template=@file 从文件读取)
+func newPrinter(format string, fields []string) (*printer, error) {
+	p := &printer{format: format, fields: fields}
+	switch {
+	case format == "json" || format == "jsonl" || format == "yaml" || format == "table":
+	case strings.HasPrefix(format, "template="):
+		text, err := readArg(strings.TrimPrefix(format, "template="))
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := template.New("output").Option("missingkey=zero").Funcs(template.FuncMap{
+			"join": func(items []any, sep string) string {
+				return formatList(items, sep)
+			},
+			"json": func(v any) (string, error) {
+				data, err := json.Marshal(v)
+				return string(data), err
+			},
+		}).Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid output template: %w", err)
+		}
+		p.format = "template"
+		p.tmpl = tmpl
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want json, jsonl, yaml, table or template=...)", format)
+	}
+	return p, nil
+}
+
+// print 将 v 按输出格式写入 w
+func (p *printer) print(w io.Writer, v any) error {
+	value, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	list, isList := rows(value)
+	if p.format == "table" {
+		return p.printTable(w, list)
+	}
+	if len(p.fields) > 0 {
+		selected := make([]any, len(list))
+		for i, row := range list {
+			selected[i] = selectFields(row, p.fields)
+		}
+		list, value = selected, selected
+		if !isList {
+			value = selected[0]
+		}
+	}
+
+	switch p.format {
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		for _, row := range list {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "template":
+		for _, row := range list {
+			var buf bytes.Buffer
+			if err := p.tmpl.Execute(&buf, row); err != nil {
+				return err
+			}
+			if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				buf.WriteByte('\n')
+			}
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// printTable 以对齐的列输出, 列为 --fields, 内存类结果的默认列, 或所有字段
+func (p *printer) printTable(w io.Writer, list []any) error {
+	columns := p.fields
+	if len(columns) == 0 {
+		columns = tableColumns(list)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range list {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			value, _ := lookup(row, column)
+			cells[i] = formatCell(value)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// toGeneric 通过 JSON 将 v 转换为 map[string]any, []any 等通用类型, 字段名与 JSON 输出一致
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// rows 返回结果中的行: 列表的元素, 带 results 列表的分页结果中的元素, 或者单个对象
+func rows(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case map[string]any:
+		if results, ok := v["results"].([]any); ok {
+			return results, true
+		}
+	case nil:
+		return nil, true
+	}
+	return []any{value}, false
+}
+
+// selectFields 只保留 row 中的 fields, 字段可以是 metadata.source 这样的路径
+func selectFields(row any, fields []string) any {
+	selected := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if value, ok := lookup(row, field); ok {
+			selected[field] = value
+		}
+	}
+	return selected
+}
+
+// lookup 按以 . 分隔的路径查找字段
+func lookup(value any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// tableColumns 返回默认列: 内存类结果使用 defaultColumns 中有值的列, 其他结果使用所有有值的标量字段
+func tableColumns(list []any) []string {
+	present := make(map[string]bool)
+	for _, row := range list {
+		if m, ok := row.(map[string]any); ok {
+			for key, value := range m {
+				if _, nested := value.(map[string]any); !nested && formatCell(value) != "" {
+					present[key] = true
+				}
+			}
+		}
+	}
+
+	if present["memory"] {
+		var columns []string
+		for _, column := range defaultColumns {
+			if present[column] {
+				columns = append(columns, column)
+			}
+		}
+		return columns
+	}
+
+	columns := make([]string, 0, len(present))
+	for key := range present {
+		columns = append(columns, key)
+	}
+	// id 和 name 排在最前面, 其余按字母顺序
+	rank := map[string]int{"id": 0, "name": 1}
+	sort.Slice(columns, func(i, j int) bool {
+		ri, iok := rank[columns[i]]
+		rj, jok := rank[columns[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		}
+		return columns[i] < columns[j]
+	})
+	return columns
+}
+
+// formatCell 将字段值格式化为单行文本
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if v == zeroTime {
+			return ""
+		}
+		return strings.ReplaceAll(v, "\n", " ")
+	case float64:
+		if v == float64(int64(v)) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', 3, 64)
+	case []any:
+		return formatList(v, ",")
+	case map[string]any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// formatList 将列表的元素格式化后用 sep 连接
+func formatList(items []any, sep string) string {
+	cells := make([]string, len(items))
+	for i, item := range items {
+		cells[i] = formatCell(item)
+	}
+	return strings.Join(cells, sep)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+func testMemories() []types.Memory {
+	return []types.Memory{
+		{ID: "m1", Memory: "likes tea", Score: 0.91234, Categories: []string{"food", "drink"}, Metadata: map[string]any{"source": "chat"}},
+		{ID: "m2", Memory: "lives in\nParis", Score: 0.5},
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		fields []string
+		value  any
+		want   string
+	}{
+		{
+			name:   "jsonl",
+			format: "jsonl",
+			fields: []string{"id", "metadata.source"},
+			value:  testMemories(),
+			want:   "{\"id\":\"m1\",\"metadata.source\":\"chat\"}\n{\"id\":\"m2\"}\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			fields: []string{"id", "score"},
+			value:  testMemories()[:1],
+			want:   "- id: m1\n  score: 0.91234\n",
+		},
+		{
+			name:   "table",
+			format: "table",
+			value:  testMemories(),
+			want: "ID  MEMORY          SCORE  CATEGORIES\n" +
+				"m1  likes tea       0.912  food,drink\n" +
+				"m2  lives in Paris  0.500  \n",
+		},
+		{
+			name:   "table with fields",
+			format: "table",
+			fields: []string{"id", "metadata.source"},
+			value:  testMemories(),
+			want:   "ID  METADATA.SOURCE\nm1  chat\nm2  \n",
+		},
+		{
+			name:   "paginated results",
+			format: "jsonl",
+			fields: []string{"name"},
+			value:  types.AllUsers{Count: 1, Results: []types.User{{Name: "alice"}}},
+			want:   "{\"name\":\"alice\"}\n",
+		},
+		{
+			name:   "template",
+			format: "template={{.id}}: {{.memory}}",
+			value:  testMemories()[:1],
+			want:   "m1: likes tea\n",
+		},
+		{
+			name:   "template on single object",
+			format: "template={{.id}} {{join .categories \"/\"}}",
+			value:  &testMemories()[0],
+			want:   "m1 food/drink\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newPrinter(tt.format, tt.fields)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, p.print(&buf, tt.value))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	_, err := newPrinter("xml", nil)
+	assert.Error(t, err)
+
+	_, err = newPrinter("template={{.id", nil)
+	assert.Error(t, err)
+}
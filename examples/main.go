@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -16,23 +17,24 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	ctx := context.Background()
 
 	// 添加内存
-	memories, err := mem0.Add("Hello, World!", types.MemoryOptions{})
+	memories, err := mem0.AddContext(ctx, "Hello, World!", types.AddOptions{UserID: "user-123"})
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Printf("Added memory: %+v\n", memories[0])
 
 	// 搜索内存
-	results, err := mem0.Search("Hello", nil)
+	results, err := mem0.SearchContext(ctx, "Hello", nil)
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Printf("Search results: %+v\n", results)
 
 	// 获取内存
-	memories_, err := mem0.Search("Hello", nil)
+	memories_, err := mem0.SearchContext(ctx, "Hello", nil)
 	if err != nil {
 		log.Fatal(err)
 	}
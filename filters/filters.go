@@ -0,0 +1,511 @@
+// Package filters 提供构建 Mem0 v2 过滤条件的类型安全 DSL
+//
+// 构建出的过滤条件可以直接用于 QueryOptions.Filters:
+//
+//	f, err := filters.Build(filters.And(
+//		filters.UserID("alice"),
+//		filters.CreatedBetween(from, to),
+//		filters.Metadata("source", "chat"),
+//	))
+//
+// Build 会在发送请求前检查已知的作用域规则, 例如 user_id 和 agent_id
+// 属于不同的隔离作用域, 不能出现在同一个 AND 条件中.
+// 参见 https://docs.mem0.ai/platform/features/v2-memory-filters
+package filters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// 已知的过滤字段
+const (
+	FieldUserID     = "user_id"
+	FieldAgentID    = "agent_id"
+	FieldAppID      = "app_id"
+	FieldRunID      = "run_id"
+	FieldCreatedAt  = "created_at"
+	FieldUpdatedAt  = "updated_at"
+	FieldCategories = "categories"
+	FieldKeywords   = "keywords"
+	FieldMetadata   = "metadata"
+)
+
+// Wildcard 匹配字段存在的任意值
+const Wildcard = "*"
+
+// Op 定义比较运算符
+type Op string
+
+const (
+	OpEq        Op = ""
+	OpNe        Op = "ne"
+	OpIn        Op = "in"
+	OpGt        Op = "gt"
+	OpGte       Op = "gte"
+	OpLt        Op = "lt"
+	OpLte       Op = "lte"
+	OpContains  Op = "contains"
+	OpIContains Op = "icontains"
+)
+
+// fieldOps 定义已知字段支持的运算符, 未列出的字段不做限制
+var fieldOps = map[string][]Op{
+	FieldUserID:     {OpEq, OpNe, OpIn},
+	FieldAgentID:    {OpEq, OpNe, OpIn},
+	FieldAppID:      {OpEq, OpNe, OpIn},
+	FieldRunID:      {OpEq, OpNe, OpIn},
+	FieldCreatedAt:  {OpEq, OpGt, OpGte, OpLt, OpLte},
+	FieldUpdatedAt:  {OpEq, OpGt, OpGte, OpLt, OpLte},
+	FieldCategories: {OpIn, OpContains},
+	FieldKeywords:   {OpContains, OpIContains},
+}
+
+// Filter 是一个过滤表达式
+type Filter interface {
+	// Map 返回表达式的 JSON 结构, 不做校验
+	Map() map[string]any
+	validate(path string, problems *[]string)
+}
+
+// Error 列出过滤条件中的所有问题
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid filter: " + strings.Join(e.Problems, "; ")
+}
+
+// Build 校验过滤条件并返回 Search 和 GetAll 需要的 JSON 结构
+func Build(f Filter) (map[string]any, error) {
+	if f == nil {
+		return nil, &Error{Problems: []string{"filter is nil"}}
+	}
+
+	if err := Validate(f); err != nil {
+		return nil, err
+	}
+	return f.Map(), nil
+}
+
+// MustBuild 与 Build 相同, 但在过滤条件无效时 panic, 适用于静态的过滤条件
+func MustBuild(f Filter) map[string]any {
+	m, err := Build(f)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Validate 检查过滤条件是否符合已知的规则
+func Validate(f Filter) error {
+	var problems []string
+	f.validate("$", &problems)
+	if len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+// Logical 是 AND, OR 或 NOT 组合
+type Logical struct {
+	Op      string
+	Filters []Filter
+}
+
+// And 匹配满足所有条件的内存
+func And(filters ...Filter) *Logical {
+	return &Logical{Op: "AND", Filters: filters}
+}
+
+// Or 匹配满足任一条件的内存
+func Or(filters ...Filter) *Logical {
+	return &Logical{Op: "OR", Filters: filters}
+}
+
+// Not 匹配不满足任何条件的内存
+func Not(filters ...Filter) *Logical {
+	return &Logical{Op: "NOT", Filters: filters}
+}
+
+// Map 返回 {"AND": [...]} 形式的 JSON 结构
+func (l *Logical) Map() map[string]any {
+	items := make([]map[string]any, len(l.Filters))
+	for i, f := range l.Filters {
+		if f != nil {
+			items[i] = f.Map()
+		}
+	}
+	return map[string]any{l.Op: items}
+}
+
+func (l *Logical) validate(path string, problems *[]string) {
+	path = fmt.Sprintf("%s.%s", path, l.Op)
+	if len(l.Filters) == 0 {
+		*problems = append(*problems, fmt.Sprintf("%s: at least one condition is required", path))
+		return
+	}
+
+	for i, f := range l.Filters {
+		if f == nil {
+			*problems = append(*problems, fmt.Sprintf("%s[%d]: condition is nil", path, i))
+			continue
+		}
+		f.validate(fmt.Sprintf("%s[%d]", path, i), problems)
+	}
+
+	if l.Op == "AND" {
+		// OR 和 NOT 分支中的 AND 在递归时单独检查
+		scopes := types.ScopedFields(l.Map())
+		if scopes[FieldUserID] && scopes[FieldAgentID] {
+			*problems = append(*problems, fmt.Sprintf("%s: user_id and agent_id belong to different scopes and cannot be combined with AND", path))
+		}
+	}
+}
+
+// Condition 是对单个字段的比较
+type Condition struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// Map 返回 {"field": value} 或 {"field": {"op": value}} 形式的 JSON 结构
+func (c *Condition) Map() map[string]any {
+	value := c.Value
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339)
+	}
+	if c.Op == OpEq {
+		return map[string]any{c.Field: value}
+	}
+	return map[string]any{c.Field: map[string]any{string(c.Op): value}}
+}
+
+func (c *Condition) validate(path string, problems *[]string) {
+	path = fmt.Sprintf("%s.%s", path, c.Field)
+	if c.Field == "" {
+		*problems = append(*problems, fmt.Sprintf("%s: field is required", path))
+		return
+	}
+	if c.Field == FieldMetadata {
+		*problems = append(*problems, fmt.Sprintf("%s: use Metadata to filter on metadata keys", path))
+		return
+	}
+
+	if ops, ok := fieldOps[c.Field]; ok && !containsOp(ops, c.Op) {
+		*problems = append(*problems, fmt.Sprintf("%s: operator %q is not supported, expected one of %s", path, opName(c.Op), opNames(ops)))
+		return
+	}
+
+	switch c.Op {
+	case OpIn:
+		if values, ok := c.Value.([]any); !ok || len(values) == 0 {
+			*problems = append(*problems, fmt.Sprintf("%s: in requires a non-empty list", path))
+		}
+	case OpContains, OpIContains:
+		if c.Field != FieldCategories {
+			if s, ok := c.Value.(string); !ok || s == "" {
+				*problems = append(*problems, fmt.Sprintf("%s: %s requires a non-empty string", path, c.Op))
+			}
+		}
+	case OpGt, OpGte, OpLt, OpLte:
+		if !isOrdered(c.Value) {
+			*problems = append(*problems, fmt.Sprintf("%s: %s requires a number, a time or a date string", path, c.Op))
+		}
+	default:
+		if c.Value == nil {
+			*problems = append(*problems, fmt.Sprintf("%s: value is required", path))
+		} else if s, ok := c.Value.(string); ok && s == "" {
+			*problems = append(*problems, fmt.Sprintf("%s: value must not be empty", path))
+		}
+	}
+
+	if c.Field == FieldCreatedAt || c.Field == FieldUpdatedAt {
+		if s, ok := c.Value.(string); ok && !isDate(s) {
+			*problems = append(*problems, fmt.Sprintf("%s: %q is not an RFC 3339 timestamp or YYYY-MM-DD date", path, s))
+		}
+	}
+}
+
+// Field 创建任意字段的比较条件
+func Field(field string, op Op, value any) *Condition {
+	return &Condition{Field: field, Op: op, Value: value}
+}
+
+// Eq 匹配字段等于 value
+func Eq(field string, value any) *Condition {
+	return Field(field, OpEq, value)
+}
+
+// Ne 匹配字段不等于 value
+func Ne(field string, value any) *Condition {
+	return Field(field, OpNe, value)
+}
+
+// In 匹配字段等于 values 中的任一值
+func In(field string, values ...any) *Condition {
+	return Field(field, OpIn, values)
+}
+
+// InStrings 与 In 相同, 接受字符串切片
+func InStrings(field string, values ...string) *Condition {
+	items := make([]any, len(values))
+	for i, v := range values {
+		items[i] = v
+	}
+	return In(field, items...)
+}
+
+// Gt 匹配字段大于 value
+func Gt(field string, value any) *Condition {
+	return Field(field, OpGt, value)
+}
+
+// Gte 匹配字段大于等于 value
+func Gte(field string, value any) *Condition {
+	return Field(field, OpGte, value)
+}
+
+// Lt 匹配字段小于 value
+func Lt(field string, value any) *Condition {
+	return Field(field, OpLt, value)
+}
+
+// Lte 匹配字段小于等于 value
+func Lte(field string, value any) *Condition {
+	return Field(field, OpLte, value)
+}
+
+// Contains 匹配字段包含 value (区分大小写)
+func Contains(field string, value string) *Condition {
+	return Field(field, OpContains, value)
+}
+
+// IContains 匹配字段包含 value (不区分大小写)
+func IContains(field string, value string) *Condition {
+	return Field(field, OpIContains, value)
+}
+
+// Any 匹配字段存在的任意值
+func Any(field string) *Condition {
+	return Eq(field, Wildcard)
+}
+
+// UserID 匹配用户 ID
+func UserID(id string) *Condition {
+	return Eq(FieldUserID, id)
+}
+
+// AgentID 匹配智能体 ID
+func AgentID(id string) *Condition {
+	return Eq(FieldAgentID, id)
+}
+
+// AppID 匹配应用 ID
+func AppID(id string) *Condition {
+	return Eq(FieldAppID, id)
+}
+
+// RunID 匹配运行 ID
+func RunID(id string) *Condition {
+	return Eq(FieldRunID, id)
+}
+
+// Categories 匹配属于任一分类的内存
+func Categories(categories ...string) *Condition {
+	return InStrings(FieldCategories, categories...)
+}
+
+// CreatedBetween 匹配在 [from, to] 之间创建的内存, 零值表示不限制该端点
+func CreatedBetween(from, to time.Time) Filter {
+	return dateRange(FieldCreatedAt, from, to)
+}
+
+// CreatedAfter 匹配在 t 之后 (包含) 创建的内存
+func CreatedAfter(t time.Time) *Condition {
+	return Gte(FieldCreatedAt, t)
+}
+
+// CreatedBefore 匹配在 t 之前 (包含) 创建的内存
+func CreatedBefore(t time.Time) *Condition {
+	return Lte(FieldCreatedAt, t)
+}
+
+// UpdatedBetween 匹配在 [from, to] 之间更新的内存, 零值表示不限制该端点
+func UpdatedBetween(from, to time.Time) Filter {
+	return dateRange(FieldUpdatedAt, from, to)
+}
+
+// Range 是同一字段上的多个比较, 序列化为 {"field": {"gte": ..., "lte": ...}}
+type Range struct {
+	Field  string
+	Bounds []*Condition
+}
+
+// dateRange 创建日期范围条件
+func dateRange(field string, from, to time.Time) Filter {
+	r := &Range{Field: field}
+	if !from.IsZero() {
+		r.Bounds = append(r.Bounds, Gte(field, from))
+	}
+	if !to.IsZero() {
+		r.Bounds = append(r.Bounds, Lte(field, to))
+	}
+	return r
+}
+
+// Map 返回 {"field": {"gte": ..., "lte": ...}} 形式的 JSON 结构
+func (r *Range) Map() map[string]any {
+	ops := make(map[string]any, len(r.Bounds))
+	for _, b := range r.Bounds {
+		if b == nil {
+			continue
+		}
+		// 等值条件和其他字段的条件不是范围比较, Build 会报告这些错误
+		bound, ok := b.Map()[r.Field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for k, v := range bound {
+			ops[k] = v
+		}
+	}
+	return map[string]any{r.Field: ops}
+}
+
+func (r *Range) validate(path string, problems *[]string) {
+	if len(r.Bounds) == 0 {
+		*problems = append(*problems, fmt.Sprintf("%s.%s: range requires at least one bound", path, r.Field))
+		return
+	}
+	for i, b := range r.Bounds {
+		if b == nil {
+			*problems = append(*problems, fmt.Sprintf("%s.%s[%d]: bound is nil", path, r.Field, i))
+			continue
+		}
+		if b.Field != r.Field {
+			*problems = append(*problems, fmt.Sprintf("%s.%s: range bound is on field %q", path, r.Field, b.Field))
+			continue
+		}
+		if b.Op == OpEq || b.Op == OpNe || b.Op == OpIn {
+			*problems = append(*problems, fmt.Sprintf("%s.%s: range bounds must use gt, gte, lt or lte", path, r.Field))
+			continue
+		}
+		b.validate(path, problems)
+	}
+
+	var from, to time.Time
+	for _, b := range r.Bounds {
+		if b == nil {
+			continue
+		}
+		t, ok := b.Value.(time.Time)
+		if !ok {
+			continue
+		}
+		switch b.Op {
+		case OpGt, OpGte:
+			from = t
+		case OpLt, OpLte:
+			to = t
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		*problems = append(*problems, fmt.Sprintf("%s.%s: range start is after its end", path, r.Field))
+	}
+}
+
+// MetadataFilter 匹配 metadata 中的键值
+type MetadataFilter struct {
+	Values map[string]any
+}
+
+// Metadata 匹配 metadata[key] 等于 value 的内存
+func Metadata(key string, value any) *MetadataFilter {
+	return &MetadataFilter{Values: map[string]any{key: value}}
+}
+
+// MetadataMap 匹配 metadata 包含 values 中所有键值的内存
+func MetadataMap(values map[string]any) *MetadataFilter {
+	return &MetadataFilter{Values: values}
+}
+
+// Map 返回 {"metadata": {"key": value}} 形式的 JSON 结构
+func (m *MetadataFilter) Map() map[string]any {
+	values := make(map[string]any, len(m.Values))
+	for k, v := range m.Values {
+		values[k] = v
+	}
+	return map[string]any{FieldMetadata: values}
+}
+
+func (m *MetadataFilter) validate(path string, problems *[]string) {
+	path = fmt.Sprintf("%s.%s", path, FieldMetadata)
+	if len(m.Values) == 0 {
+		*problems = append(*problems, fmt.Sprintf("%s: at least one key is required", path))
+	}
+	for k, v := range m.Values {
+		if k == "" {
+			*problems = append(*problems, fmt.Sprintf("%s: key must not be empty", path))
+		}
+		if v == nil {
+			*problems = append(*problems, fmt.Sprintf("%s.%s: value is required", path, k))
+		}
+	}
+}
+
+// containsOp 判断 ops 中是否包含 op
+func containsOp(ops []Op, op Op) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// opName 返回运算符的名称, 相等运算符返回 "eq"
+func opName(op Op) string {
+	if op == OpEq {
+		return "eq"
+	}
+	return string(op)
+}
+
+// opNames 返回排序后的运算符名称列表
+func opNames(ops []Op) string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = opName(op)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// isOrdered 判断值是否可以用于大小比较
+func isOrdered(v any) bool {
+	switch v := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	case time.Time:
+		return !v.IsZero()
+	case string:
+		return isDate(v)
+	}
+	return false
+}
+
+// isDate 判断字符串是否为 RFC 3339 时间或 YYYY-MM-DD 日期
+func isDate(s string) bool {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
@@ -0,0 +1,180 @@
+package filters
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildJSON(t *testing.T) {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{
+			name:   "equality",
+			filter: UserID("alice"),
+			want:   `{"user_id":"alice"}`,
+		},
+		{
+			name:   "wildcard",
+			filter: Any(FieldRunID),
+			want:   `{"run_id":"*"}`,
+		},
+		{
+			name:   "in",
+			filter: InStrings(FieldAgentID, "a", "b"),
+			want:   `{"agent_id":{"in":["a","b"]}}`,
+		},
+		{
+			name: "and with date range and metadata",
+			filter: And(
+				UserID("alice"),
+				CreatedBetween(from, to),
+				Metadata("source", "chat"),
+			),
+			want: `{"AND":[{"user_id":"alice"},{"created_at":{"gte":"2024-07-01T00:00:00Z","lte":"2024-07-31T00:00:00Z"}},{"metadata":{"source":"chat"}}]}`,
+		},
+		{
+			name:   "or of scopes",
+			filter: Or(UserID("alice"), AgentID("bot")),
+			want:   `{"OR":[{"user_id":"alice"},{"agent_id":"bot"}]}`,
+		},
+		{
+			name:   "not",
+			filter: Not(Categories("spam")),
+			want:   `{"NOT":[{"categories":{"in":["spam"]}}]}`,
+		},
+		{
+			name:   "string operators",
+			filter: And(Contains(FieldKeywords, "Go"), IContains(FieldKeywords, "rust")),
+			want:   `{"AND":[{"keywords":{"contains":"Go"}},{"keywords":{"icontains":"rust"}}]}`,
+		},
+		{
+			name:   "comparison on custom field",
+			filter: And(Gt("score", 1), Lt("score", 5.5), Lte(FieldUpdatedAt, "2024-07-01")),
+			want:   `{"AND":[{"score":{"gt":1}},{"score":{"lt":5.5}},{"updated_at":{"lte":"2024-07-01"}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Build(tt.filter)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(m)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestBuildValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		problem string
+	}{
+		{
+			name:    "user and agent in and",
+			filter:  And(UserID("alice"), AgentID("bot")),
+			problem: "user_id and agent_id",
+		},
+		{
+			name:    "user and agent in nested and",
+			filter:  And(UserID("alice"), And(RunID("r"), AgentID("bot"))),
+			problem: "user_id and agent_id",
+		},
+		{
+			name:    "empty logical",
+			filter:  Or(),
+			problem: "at least one condition",
+		},
+		{
+			name:    "empty in",
+			filter:  In(FieldUserID),
+			problem: "non-empty list",
+		},
+		{
+			name:    "unsupported operator",
+			filter:  Gt(FieldUserID, 1),
+			problem: `operator "gt" is not supported`,
+		},
+		{
+			name:    "invalid date",
+			filter:  Gte(FieldCreatedAt, "yesterday"),
+			problem: "requires a number, a time or a date string",
+		},
+		{
+			name:    "inverted range",
+			filter:  CreatedBetween(time.Now(), time.Now().Add(-time.Hour)),
+			problem: "start is after its end",
+		},
+		{
+			name:    "empty range",
+			filter:  CreatedBetween(time.Time{}, time.Time{}),
+			problem: "at least one bound",
+		},
+		{
+			name:    "metadata via Eq",
+			filter:  Eq(FieldMetadata, map[string]any{"k": "v"}),
+			problem: "use Metadata",
+		},
+		{
+			name:    "equality bound",
+			filter:  &Range{Field: FieldCreatedAt, Bounds: []*Condition{Eq(FieldCreatedAt, "2024-01-01")}},
+			problem: "range bounds must use gt, gte, lt or lte",
+		},
+		{
+			name:    "bound on another field",
+			filter:  &Range{Field: FieldCreatedAt, Bounds: []*Condition{Gte(FieldUpdatedAt, "2024-01-01")}},
+			problem: `range bound is on field "updated_at"`,
+		},
+		{
+			name:    "empty value",
+			filter:  UserID(""),
+			problem: "must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(tt.filter)
+			var ferr *Error
+			require.ErrorAs(t, err, &ferr)
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+func TestScopeRulesAllowWildcardsAndOr(t *testing.T) {
+	_, err := Build(And(UserID("alice"), Any(FieldAgentID)))
+	assert.NoError(t, err)
+
+	_, err = Build(Or(UserID("alice"), AgentID("bot")))
+	assert.NoError(t, err)
+
+	_, err = Build(And(UserID("alice"), Ne(FieldAgentID, "bot")))
+	assert.NoError(t, err)
+}
+
+func TestRangeMapSkipsInvalidBounds(t *testing.T) {
+	r := &Range{Field: FieldCreatedAt, Bounds: []*Condition{
+		Eq(FieldCreatedAt, "2024-01-01"),
+		Gte(FieldUpdatedAt, "2024-01-01"),
+		Lte(FieldCreatedAt, "2024-12-31"),
+	}}
+	assert.Equal(t, map[string]any{FieldCreatedAt: map[string]any{"lte": "2024-12-31"}}, r.Map())
+}
+
+func TestMustBuildPanics(t *testing.T) {
+	assert.Panics(t, func() { MustBuild(And()) })
+	assert.Equal(t, map[string]any{"app_id": "app"}, MustBuild(AppID("app")))
+}
@@ -0,0 +1,319 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// Match 在本地判断内存是否满足 v2 过滤条件, 同一层的多个键是 AND 关系
+// 支持 AND, OR, NOT, 实体字段, created_at, updated_at, categories, keywords 和 metadata,
+// 其他字段返回错误. 实体字段的通配符 "*" 匹配任意值, 包括没有该字段的内存,
+// 与客户端为缺少的字段填充通配符的行为一致.
+// filters 可以是 Build 的结果或任意能编码为 JSON 的 map
+func Match(m *types.Memory, filters map[string]any) (bool, error) {
+	// 统一为 JSON 解码后的形式, 例如 []map[string]any 变为 []any, 数字变为 float64
+	var normalized map[string]any
+	if err := normalize(filters, &normalized); err != nil {
+		return false, fmt.Errorf("invalid filters: %v", err)
+	}
+	if m.Metadata != nil {
+		// 解码到新的 map, 不修改调用方的 metadata
+		memory := *m
+		memory.Metadata = nil
+		if err := normalize(m.Metadata, &memory.Metadata); err != nil {
+			return false, fmt.Errorf("invalid metadata: %v", err)
+		}
+		m = &memory
+	}
+	return matchFilters(m, normalized)
+}
+
+// normalize 通过 JSON 编码和解码将 v 转换到 out
+func normalize(v, out any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// matchFilters 判断内存是否满足已经统一为 JSON 形式的过滤条件
+func matchFilters(m *types.Memory, filters map[string]any) (bool, error) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	// 固定顺序使错误信息稳定
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		ok, err := matchField(m, key, filters[key])
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchField 判断内存是否满足一个键的条件
+func matchField(m *types.Memory, key string, cond any) (bool, error) {
+	switch key {
+	case "AND", "OR", "NOT":
+		return matchLogical(m, key, cond)
+	case "user_id":
+		return matchEntity(m.UserID, key, cond)
+	case "agent_id":
+		return matchEntity(m.AgentID, key, cond)
+	case "app_id":
+		return matchEntity(m.AppID, key, cond)
+	case "run_id":
+		return matchEntity(m.RunID, key, cond)
+	case "created_at":
+		return matchTime(m.CreatedAt, key, cond)
+	case "updated_at":
+		return matchTime(m.UpdatedAt, key, cond)
+	case "categories":
+		return matchCategories(m.Categories, cond)
+	case "keywords":
+		return matchKeywords(m.Memory, cond)
+	case "metadata":
+		return matchMetadata(m.Metadata, cond)
+	}
+	return false, fmt.Errorf("unsupported filter field %q", key)
+}
+
+// matchLogical 处理 {"AND": [...]}, {"OR": [...]} 和 {"NOT": [...]}, NOT 匹配不满足任何条件的内存
+func matchLogical(m *types.Memory, op string, cond any) (bool, error) {
+	items, ok := cond.([]any)
+	if !ok || len(items) == 0 {
+		return false, fmt.Errorf("%s requires a non-empty list", op)
+	}
+
+	matched := 0
+	for i, item := range items {
+		filters, ok := item.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("%s[%d] must be an object", op, i)
+		}
+		ok, err := matchFilters(m, filters)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			matched++
+		}
+	}
+
+	switch op {
+	case "AND":
+		return matched == len(items), nil
+	case "OR":
+		return matched > 0, nil
+	}
+	return matched == 0, nil
+}
+
+// operators 将 {"op": value} 形式的条件拆分为运算符和值, 直接给出的值视为 eq
+func operators(cond any) map[string]any {
+	if ops, ok := cond.(map[string]any); ok {
+		return ops
+	}
+	return map[string]any{"eq": cond}
+}
+
+// matchEntity 支持 eq, ne 和 in
+func matchEntity(actual, field string, cond any) (bool, error) {
+	for op, value := range operators(cond) {
+		var ok bool
+		switch op {
+		case "eq":
+			ok = value == Wildcard || value == actual
+		case "ne":
+			ok = value != actual
+		case "in":
+			list, isList := value.([]any)
+			if !isList {
+				return false, fmt.Errorf("%s: in requires a list", field)
+			}
+			ok = contains(list, actual)
+		default:
+			return false, fmt.Errorf("%s: unsupported operator %q", field, op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchTime 支持 eq, gt, gte, lt 和 lte, 值可以是 RFC 3339 时间或 YYYY-MM-DD 日期
+// 使用日期比较时只比较日期部分
+func matchTime(actual time.Time, field string, cond any) (bool, error) {
+	for op, value := range operators(cond) {
+		s, _ := value.(string)
+		bound, dateOnly, err := parseTime(s)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", field, err)
+		}
+		t := actual.UTC()
+		if dateOnly {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		var ok bool
+		switch op {
+		case "eq":
+			ok = t.Equal(bound)
+		case "gt":
+			ok = t.After(bound)
+		case "gte":
+			ok = !t.Before(bound)
+		case "lt":
+			ok = t.Before(bound)
+		case "lte":
+			ok = !t.After(bound)
+		default:
+			return false, fmt.Errorf("%s: unsupported operator %q", field, op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseTime 解析 RFC 3339 时间或 YYYY-MM-DD 日期
+func parseTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), false, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not an RFC 3339 timestamp or YYYY-MM-DD date", s)
+}
+
+// matchCategories 支持 in (属于任一分类) 和 contains (属于该分类), 直接给出的字符串视为 contains
+func matchCategories(categories []string, cond any) (bool, error) {
+	for op, value := range operators(cond) {
+		var ok bool
+		switch op {
+		case "eq", "contains":
+			s, isString := value.(string)
+			if !isString {
+				return false, fmt.Errorf("categories: %s requires a string", op)
+			}
+			ok = contains(toAny(categories), s)
+		case "in":
+			list, isList := value.([]any)
+			if !isList {
+				return false, fmt.Errorf("categories: in requires a list")
+			}
+			for _, category := range categories {
+				ok = ok || contains(list, category)
+			}
+		default:
+			return false, fmt.Errorf("categories: unsupported operator %q", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchKeywords 支持 contains 和 icontains, 在内存文本中查找子串
+func matchKeywords(text string, cond any) (bool, error) {
+	for op, value := range operators(cond) {
+		s, isString := value.(string)
+		if !isString {
+			return false, fmt.Errorf("keywords: %s requires a string", op)
+		}
+		var ok bool
+		switch op {
+		case "contains":
+			ok = strings.Contains(text, s)
+		case "icontains":
+			ok = strings.Contains(strings.ToLower(text), strings.ToLower(s))
+		default:
+			return false, fmt.Errorf("keywords: unsupported operator %q", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchMetadata 要求 metadata 包含条件中的所有键, 值相等或满足 ne, in, contains, icontains 运算符
+func matchMetadata(metadata map[string]any, cond any) (bool, error) {
+	values, ok := cond.(map[string]any)
+	if !ok {
+		return false, fmt.Errorf("metadata must be an object")
+	}
+
+	for key, want := range values {
+		actual, exists := metadata[key]
+		ops, isOps := want.(map[string]any)
+		if !isOps {
+			if !exists || !reflect.DeepEqual(actual, want) {
+				return false, nil
+			}
+			continue
+		}
+
+		for op, value := range ops {
+			var ok bool
+			switch op {
+			case "eq":
+				ok = exists && reflect.DeepEqual(actual, value)
+			case "ne":
+				ok = !reflect.DeepEqual(actual, value)
+			case "in":
+				list, isList := value.([]any)
+				if !isList {
+					return false, fmt.Errorf("metadata.%s: in requires a list", key)
+				}
+				ok = exists && contains(list, actual)
+			case "contains", "icontains":
+				s, _ := actual.(string)
+				sub, _ := value.(string)
+				if op == "icontains" {
+					s, sub = strings.ToLower(s), strings.ToLower(sub)
+				}
+				ok = exists && strings.Contains(s, sub)
+			default:
+				return false, fmt.Errorf("metadata.%s: unsupported operator %q", key, op)
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// contains 判断 list 中是否有与 v 相等的值
+func contains(list []any, v any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// toAny 将字符串切片转换为 []any
+func toAny(items []string) []any {
+	out := make([]any, len(items))
+	for i, item := range items {
+		out[i] = item
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+func TestMatch(t *testing.T) {
+	memory := &types.Memory{
+		Memory:     "Alice likes green tea",
+		UserID:     "alice",
+		Categories: []string{"food"},
+		CreatedAt:  time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC),
+		Metadata:   map[string]any{"source": "chat", "rating": 5},
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]any
+		want    bool
+	}{
+		{
+			name: "built filter",
+			filters: MustBuild(And(
+				UserID("alice"),
+				CreatedBetween(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)),
+				Metadata("source", "chat"),
+			)),
+			want: true,
+		},
+		{
+			name: "typed go values",
+			filters: map[string]any{
+				"AND": []map[string]any{
+					{"user_id": "alice"},
+					{"metadata": map[string]any{"rating": 5}},
+					{"categories": map[string]any{"in": []string{"food", "drink"}}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:    "wildcard matches missing entity",
+			filters: map[string]any{"user_id": "alice", "agent_id": "*"},
+			want:    true,
+		},
+		{
+			name:    "not",
+			filters: MustBuild(Not(IContains(FieldKeywords, "GREEN"))),
+			want:    false,
+		},
+		{
+			name:    "other user",
+			filters: MustBuild(Or(UserID("bob"), Metadata("source", "email"))),
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Match(memory, tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+
+	_, err := Match(memory, map[string]any{"score": map[string]any{"gt": 1}})
+	require.Error(t, err)
+}
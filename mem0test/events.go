@@ -0,0 +1,50 @@
+package mem0test
+
+import (
+	"net/http"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// defaultEventsPageSize 是 GET /v1/events/ 默认的每页条目数
+const defaultEventsPageSize = 50
+
+// record 记录一个已经完成的事件, 调用方需持有 mu
+func (s *Server) record(eventType types.EventType, payload any, results []types.Memory) types.Event {
+	now := s.now().UTC()
+	event := types.Event{
+		ID:          s.nextID("evt"),
+		EventType:   eventType,
+		Status:      types.EventStatusSUCCEEDED,
+		Metadata:    map[string]any{},
+		Results:     []any{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		StartedAt:   now,
+		CompletedAt: now,
+	}
+	remarshal(payload, &event.Payload)
+	remarshal(results, &event.Results)
+	s.events = append(s.events, event)
+	return event
+}
+
+func (s *Server) getEvent(r *http.Request, id string) (any, error) {
+	for _, event := range s.events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "Event with id %s not found", id)
+}
+
+// listEvents 按创建时间倒序分页返回事件, next 是下一页的完整 URL
+func (s *Server) listEvents(r *http.Request, _ string) (any, error) {
+	events := make([]types.Event, len(s.events))
+	for i, event := range s.events {
+		events[len(events)-1-i] = event
+	}
+
+	results, next := page(s, r, events, defaultEventsPageSize)
+	return types.GetEventsResponse{Count: int64(len(events)), Results: results, Next: next}, nil
+}
@@ -0,0 +1,505 @@
+package mem0test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bytectlgo/mem0-go/filters"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// maxBatchSize 是批量接口每次请求的条目上限, 与服务端相同
+const maxBatchSize = 1000
+
+// addRequest 是 POST /v1/memories/ 的请求体
+type addRequest struct {
+	types.AddEventPayload
+	Timestamp int64 `json:"timestamp"`
+	AsyncMode *bool `json:"async_mode"`
+}
+
+func (s *Server) add(r *http.Request, _ string) (any, error) {
+	var req addRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.UserID == "" && req.AgentID == "" && req.AppID == "" && req.RunID == "" {
+		return nil, errorf(http.StatusBadRequest, "at least one of user_id, agent_id, app_id or run_id is required")
+	}
+	if len(req.Messages) == 0 {
+		return nil, errorf(http.StatusBadRequest, "messages are required")
+	}
+
+	infer := req.Infer == nil || *req.Infer
+	var added []types.Memory
+	for _, message := range req.Messages {
+		text := strings.TrimSpace(message.Content)
+		if text == "" || (infer && message.Role != "user") {
+			continue
+		}
+		memory := types.Memory{
+			Memory:   text,
+			UserID:   req.UserID,
+			AgentID:  req.AgentID,
+			AppID:    req.AppID,
+			RunID:    req.RunID,
+			Metadata: req.Metadata,
+		}
+		// 推理时相同实体下已有的内容不会重复添加
+		if infer && s.find(func(m *types.Memory) bool { return sameEntity(m, &memory) && m.Memory == text }) != nil {
+			continue
+		}
+		if req.Timestamp > 0 {
+			memory.CreatedAt = time.Unix(req.Timestamp, 0).UTC()
+		}
+		memory = s.insert(memory, req.Messages)
+		memory.Event = types.EventTypeMemoryAdd
+		added = append(added, memory)
+	}
+	if added == nil {
+		added = []types.Memory{}
+	}
+
+	event := s.record(types.EventTypeMemoryAdd, req.AddEventPayload, added)
+	if req.AsyncMode != nil && !*req.AsyncMode {
+		return added, nil
+	}
+	return []types.MemoryAddAEvent{{
+		Message: "Memory processing has been queued for background execution",
+		Status:  types.EventStatusPENDING,
+		EventID: event.ID,
+	}}, nil
+}
+
+// insert 保存内存并记录 ADD 历史, 调用方需持有 mu
+func (s *Server) insert(memory types.Memory, input []types.Message) types.Memory {
+	now := s.now().UTC()
+	if memory.ID == "" {
+		memory.ID = s.nextID("mem")
+	}
+	if memory.Hash == "" {
+		memory.Hash = hash(memory.Memory)
+	}
+	if memory.CreatedAt.IsZero() {
+		memory.CreatedAt = now
+	}
+	if memory.UpdatedAt.IsZero() {
+		memory.UpdatedAt = memory.CreatedAt
+	}
+	if memory.Categories == nil {
+		memory.Categories = s.categorize(memory.Memory)
+	}
+	if input == nil {
+		input = []types.Message{{Role: "user", Content: memory.Memory}}
+	}
+	memory = clone(memory)
+	s.memories = append(s.memories, memory)
+
+	s.history[memory.ID] = append(s.history[memory.ID], types.MemoryHistory{
+		ID:         s.nextID("hist"),
+		MemoryID:   memory.ID,
+		Input:      input,
+		NewMemory:  memory.Memory,
+		UserID:     memory.UserID,
+		Categories: memory.Categories,
+		Event:      types.EventTypeMemoryAdd,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	return clone(memory)
+}
+
+// categorize 返回名称出现在文本中的项目自定义分类
+func (s *Server) categorize(text string) []string {
+	var categories []string
+	lower := strings.ToLower(text)
+	for _, category := range s.project.categories {
+		if strings.Contains(lower, strings.ToLower(category.CategoryName)) {
+			categories = append(categories, category.CategoryName)
+		}
+	}
+	return categories
+}
+
+// find 返回第一条满足 match 的内存, 调用方需持有 mu
+func (s *Server) find(match func(*types.Memory) bool) *types.Memory {
+	for i := range s.memories {
+		if match(&s.memories[i]) {
+			return &s.memories[i]
+		}
+	}
+	return nil
+}
+
+// memory 返回指定 ID 的内存, 不存在时返回 404
+func (s *Server) memory(id string) (*types.Memory, error) {
+	if m := s.find(func(m *types.Memory) bool { return m.ID == id }); m != nil {
+		return m, nil
+	}
+	return nil, errorf(http.StatusNotFound, "Memory with id %s not found", id)
+}
+
+func (s *Server) get(r *http.Request, id string) (any, error) {
+	m, err := s.memory(id)
+	if err != nil {
+		return nil, err
+	}
+	return clone(*m), nil
+}
+
+func (s *Server) update(r *http.Request, id string) (any, error) {
+	var req types.UpdateEventPayload
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	m, err := s.memory(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Text == "" {
+		return nil, errorf(http.StatusBadRequest, "text is required")
+	}
+
+	s.updateText(m, req.Text)
+	req.MemoryID = id
+	updated := clone(*m)
+	updated.Event = types.EventTypeMemoryUpdate
+	s.record(types.EventTypeMemoryUpdate, req, []types.Memory{updated})
+	return []types.Memory{clone(*m)}, nil
+}
+
+// updateText 修改内存的文本并记录 UPDATE 历史
+func (s *Server) updateText(m *types.Memory, text string) {
+	now := s.now().UTC()
+	old := m.Memory
+	m.Memory = text
+	m.Hash = hash(text)
+	m.UpdatedAt = now
+
+	s.history[m.ID] = append(s.history[m.ID], types.MemoryHistory{
+		ID:         s.nextID("hist"),
+		MemoryID:   m.ID,
+		Input:      []types.Message{{Role: "user", Content: text}},
+		OldMemory:  old,
+		NewMemory:  text,
+		UserID:     m.UserID,
+		Categories: m.Categories,
+		Event:      types.EventTypeMemoryUpdate,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
+
+func (s *Server) delete(r *http.Request, id string) (any, error) {
+	if _, err := s.memory(id); err != nil {
+		return nil, err
+	}
+	deleted := s.remove(func(m *types.Memory) bool { return m.ID == id })
+	s.record(types.EventTypeMemoryDelete, types.DeleteEventPayload{MemoryID: id}, deleted)
+	return map[string]string{"message": "Memory deleted successfully!"}, nil
+}
+
+func (s *Server) deleteAll(r *http.Request, _ string) (any, error) {
+	query := r.URL.Query()
+	req := types.DeleteEventPayload{
+		UserID:  query.Get("user_id"),
+		AgentID: query.Get("agent_id"),
+		AppID:   query.Get("app_id"),
+		RunID:   query.Get("run_id"),
+	}
+	entity := types.Memory{UserID: req.UserID, AgentID: req.AgentID, AppID: req.AppID, RunID: req.RunID}
+	if entity.UserID == "" && entity.AgentID == "" && entity.AppID == "" && entity.RunID == "" {
+		return nil, errorf(http.StatusBadRequest, "at least one of user_id, agent_id, app_id or run_id is required")
+	}
+
+	deleted := s.remove(func(m *types.Memory) bool {
+		return (entity.UserID == "" || m.UserID == entity.UserID) &&
+			(entity.AgentID == "" || m.AgentID == entity.AgentID) &&
+			(entity.AppID == "" || m.AppID == entity.AppID) &&
+			(entity.RunID == "" || m.RunID == entity.RunID)
+	})
+	s.record(types.EventTypeMemoryDelete, req, deleted)
+	return map[string]string{"message": "Memories deleted successfully!"}, nil
+}
+
+// remove 删除满足 match 的内存并记录 DELETE 历史, 返回被删除的内存
+func (s *Server) remove(match func(*types.Memory) bool) []types.Memory {
+	now := s.now().UTC()
+	var deleted []types.Memory
+	kept := s.memories[:0]
+	for _, m := range s.memories {
+		if !match(&m) {
+			kept = append(kept, m)
+			continue
+		}
+		m.Event = types.EventTypeMemoryDelete
+		deleted = append(deleted, m)
+		s.history[m.ID] = append(s.history[m.ID], types.MemoryHistory{
+			ID:         s.nextID("hist"),
+			MemoryID:   m.ID,
+			OldMemory:  m.Memory,
+			UserID:     m.UserID,
+			Categories: m.Categories,
+			Event:      types.EventTypeMemoryDelete,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+	s.memories = kept
+	return deleted
+}
+
+func (s *Server) getHistory(r *http.Request, id string) (any, error) {
+	history, ok := s.history[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "Memory with id %s not found", id)
+	}
+	return history, nil
+}
+
+// listRequest 是 POST /v2/memories/ 的请求体
+type listRequest struct {
+	Filters  map[string]any `json:"filters"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+func (s *Server) list(r *http.Request, _ string) (any, error) {
+	var req listRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	memories, err := s.filter(req.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Page > 0 {
+		size := req.PageSize
+		if size <= 0 {
+			size = 100
+		}
+		start := (req.Page - 1) * size
+		switch {
+		case start >= len(memories):
+			memories = []types.Memory{}
+		case start+size < len(memories):
+			memories = memories[start : start+size]
+		default:
+			memories = memories[start:]
+		}
+	}
+	return memories, nil
+}
+
+// filter 返回满足 v2 过滤条件的内存的副本
+func (s *Server) filter(conds map[string]any) ([]types.Memory, error) {
+	memories := []types.Memory{}
+	for i := range s.memories {
+		ok, err := filters.Match(&s.memories[i], conds)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid filters: %v", err)
+		}
+		if ok {
+			memories = append(memories, clone(s.memories[i]))
+		}
+	}
+	return memories, nil
+}
+
+// searchRequest 是 POST /v2/memories/search/ 的请求体
+type searchRequest struct {
+	types.SearchEventPayload
+	Threshold  *float64 `json:"threshold"`
+	Categories []string `json:"categories"`
+	UserID     string   `json:"user_id"`
+	AgentID    string   `json:"agent_id"`
+	AppID      string   `json:"app_id"`
+	RunID      string   `json:"run_id"`
+}
+
+// search 按词频的余弦相似度排序, 只返回相似度大于 threshold (默认为 0) 的前 top_k (默认为 10) 条内存
+func (s *Server) search(r *http.Request, _ string) (any, error) {
+	var req searchRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, errorf(http.StatusBadRequest, "query is required")
+	}
+
+	filters := req.Filters
+	if filters == nil {
+		// v1 搜索在请求体中指定实体
+		filters = make(map[string]any)
+		for key, value := range map[string]string{"user_id": req.UserID, "agent_id": req.AgentID, "app_id": req.AppID, "run_id": req.RunID} {
+			if value != "" {
+				filters[key] = value
+			}
+		}
+	}
+	if len(req.Categories) > 0 {
+		categories := make([]any, len(req.Categories))
+		for i, c := range req.Categories {
+			categories[i] = c
+		}
+		filters = map[string]any{"AND": []any{filters, map[string]any{"categories": map[string]any{"in": categories}}}}
+	}
+
+	candidates, err := s.filter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := 0.0
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+	query := termFrequencies(req.Query)
+	results := []types.Memory{}
+	for _, m := range candidates {
+		m.Score = cosine(query, termFrequencies(m.Memory))
+		if m.Score > threshold {
+			results = append(results, m)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	topK := req.TopK
+	if topK <= 0 {
+		topK = 10
+	}
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// termFrequencies 将文本按字母和数字以外的字符切分, 返回小写词的词频
+func termFrequencies(text string) map[string]float64 {
+	tf := make(map[string]float64)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		tf[word]++
+	}
+	return tf
+}
+
+// cosine 返回两个词频向量的余弦相似度
+func cosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for word, x := range a {
+		dot += x * b[word]
+		na += x * x
+	}
+	for _, y := range b {
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func (s *Server) batchUpdate(r *http.Request, _ string) (any, error) {
+	var raw any
+	if err := decode(r, &raw); err != nil {
+		return nil, err
+	}
+	var items []types.MemoryUpdateBody
+	if err := unwrapBatch(raw, &items); err != nil {
+		return nil, err
+	}
+	if len(items) > maxBatchSize {
+		return nil, errorf(http.StatusBadRequest, "cannot update more than %d memories at once", maxBatchSize)
+	}
+
+	// 任一内存不存在时不修改任何内存
+	targets := make([]*types.Memory, len(items))
+	for i, item := range items {
+		m, err := s.memory(item.MemoryID)
+		if err != nil {
+			return nil, err
+		}
+		targets[i] = m
+	}
+	var updated []types.Memory
+	for i, item := range items {
+		s.updateText(targets[i], item.Text)
+		m := clone(*targets[i])
+		m.Event = types.EventTypeMemoryUpdate
+		updated = append(updated, m)
+	}
+	s.record(types.EventTypeMemoryUpdate, map[string]any{"memories": items}, updated)
+	return map[string]string{"message": "Successfully updated memories"}, nil
+}
+
+func (s *Server) batchDelete(r *http.Request, _ string) (any, error) {
+	var raw any
+	if err := decode(r, &raw); err != nil {
+		return nil, err
+	}
+	var ids []string
+	if list, ok := raw.([]any); ok {
+		for _, item := range list {
+			id, _ := item.(string)
+			ids = append(ids, id)
+		}
+	} else {
+		var items []struct {
+			MemoryID string `json:"memory_id"`
+		}
+		if err := unwrapBatch(raw, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			ids = append(ids, item.MemoryID)
+		}
+	}
+	if len(ids) > maxBatchSize {
+		return nil, errorf(http.StatusBadRequest, "cannot delete more than %d memories at once", maxBatchSize)
+	}
+
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, err := s.memory(id); err != nil {
+			return nil, err
+		}
+		remove[id] = true
+	}
+	deleted := s.remove(func(m *types.Memory) bool { return remove[m.ID] })
+	s.record(types.EventTypeMemoryDelete, types.DeleteEventPayload{MemoryIDs: ids}, deleted)
+	return map[string]string{"message": "Successfully deleted memories"}, nil
+}
+
+// unwrapBatch 将条目列表或 {"memories": [...]} 形式的请求体解码到 v
+func unwrapBatch(raw any, v any) error {
+	if m, ok := raw.(map[string]any); ok {
+		raw = m["memories"]
+	}
+	if _, ok := raw.([]any); !ok {
+		return errorf(http.StatusBadRequest, "expected a list of memories")
+	}
+	if err := remarshal(raw, v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid memories: %v", err)
+	}
+	return nil
+}
+
+// sameEntity 判断两条内存是否属于同一实体
+func sameEntity(a, b *types.Memory) bool {
+	return a.UserID == b.UserID && a.AgentID == b.AgentID && a.AppID == b.AppID && a.RunID == b.RunID
+}
+
+// hash 返回文本的 MD5, 与服务端计算内存 hash 的方式相同
+func hash(text string) string {
+	sum := md5.Sum([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
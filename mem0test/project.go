@@ -0,0 +1,242 @@
+package mem0test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// entityTypes 是 /v1/users/ 返回的实体类型和对应的内存字段
+var entityTypes = []struct {
+	kind string
+	id   func(*types.Memory) string
+}{
+	{"user", func(m *types.Memory) string { return m.UserID }},
+	{"agent", func(m *types.Memory) string { return m.AgentID }},
+	{"app", func(m *types.Memory) string { return m.AppID }},
+	{"run", func(m *types.Memory) string { return m.RunID }},
+}
+
+// defaultUsersPageSize 是 GET /v1/users/ 默认的每页条目数
+const defaultUsersPageSize = 100
+
+// users 返回拥有内存的所有实体, 按第一条内存的添加顺序排列
+func (s *Server) users(r *http.Request, _ string) (any, error) {
+	var users []types.User
+	index := make(map[string]int)
+	for i := range s.memories {
+		m := &s.memories[i]
+		for _, entity := range entityTypes {
+			id := entity.id(m)
+			if id == "" {
+				continue
+			}
+			key := entity.kind + "/" + id
+			j, ok := index[key]
+			if !ok {
+				j = len(users)
+				index[key] = j
+				users = append(users, types.User{ID: id, Name: id, Type: entity.kind, Owner: UserEmail, CreatedAt: m.CreatedAt})
+			}
+			users[j].TotalMemories++
+			if m.UpdatedAt.After(users[j].UpdatedAt) {
+				users[j].UpdatedAt = m.UpdatedAt
+			}
+		}
+	}
+
+	results, next := page(s, r, users, defaultUsersPageSize)
+	response := types.AllUsers{Count: len(users), Results: results}
+	if next != "" {
+		response.Next = next
+	}
+	return response, nil
+}
+
+// deleteUser 删除属于该 ID 的用户, 智能体, 应用或运行的所有内存
+func (s *Server) deleteUser(r *http.Request, id string) (any, error) {
+	deleted := s.remove(func(m *types.Memory) bool {
+		return m.UserID == id || m.AgentID == id || m.AppID == id || m.RunID == id
+	})
+	if len(deleted) == 0 {
+		return nil, errorf(http.StatusNotFound, "Entity %s not found", id)
+	}
+	s.record(types.EventTypeMemoryDelete, types.DeleteEventPayload{MemoryIDs: memoryIDs(deleted)}, deleted)
+	return map[string]string{"message": "Entity deleted successfully!"}, nil
+}
+
+func (s *Server) deleteUsers(r *http.Request, _ string) (any, error) {
+	deleted := s.remove(func(*types.Memory) bool { return true })
+	if len(deleted) > 0 {
+		s.record(types.EventTypeMemoryDelete, types.DeleteEventPayload{MemoryIDs: memoryIDs(deleted)}, deleted)
+	}
+	return map[string]string{"message": "All entities deleted successfully!"}, nil
+}
+
+// memoryIDs 返回内存的 ID
+func memoryIDs(memories []types.Memory) []string {
+	ids := make([]string, len(memories))
+	for i, m := range memories {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+func (s *Server) getProject(r *http.Request, _ string) (any, error) {
+	var response types.ProjectResponse
+	fields := r.URL.Query().Get("fields")
+	want := func(field string) bool {
+		return fields == "" || strings.Contains(","+fields+",", ","+field+",")
+	}
+	if want("custom_instructions") {
+		response.CustomInstructions = s.project.instructions
+	}
+	if want("custom_categories") {
+		for _, category := range s.project.categories {
+			response.CustomCategories = append(response.CustomCategories, category.CategoryName)
+		}
+	}
+	return response, nil
+}
+
+// updateProject 更新项目设置, 自定义分类用于之后添加的内存
+func (s *Server) updateProject(r *http.Request, _ string) (any, error) {
+	var req types.PromptUpdatePayload
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.CustomInstructions != "" {
+		s.project.instructions = req.CustomInstructions
+	}
+	if req.CustomCategories != nil {
+		s.project.categories = req.CustomCategories
+	}
+	return map[string]string{"message": "Updated custom instructions"}, nil
+}
+
+func (s *Server) listWebhooks(r *http.Request, _ string) (any, error) {
+	projectID := r.URL.Query().Get("project_id")
+	webhooks := []types.Webhook{}
+	for _, webhook := range s.webhooks {
+		if projectID == "" || webhook.Project == projectID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+// createWebhook 创建 webhook, 同一项目下的 url 必须唯一
+func (s *Server) createWebhook(r *http.Request, projectID string) (any, error) {
+	var req types.WebhookPayload
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if err := validateWebhook(req); err != nil {
+		return nil, err
+	}
+	for _, webhook := range s.webhooks {
+		if webhook.Project == projectID && webhook.URL == req.URL {
+			return nil, &apiError{status: http.StatusBadRequest, body: map[string][]string{
+				"non_field_errors": {"The fields project, url must make a unique set."},
+			}}
+		}
+	}
+
+	now := s.now().UTC()
+	webhook := types.Webhook{
+		WebhookID:  s.nextID("wh"),
+		Name:       req.Name,
+		URL:        req.URL,
+		Owner:      UserEmail,
+		Project:    projectID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		IsActive:   true,
+		EventTypes: req.EventTypes,
+	}
+	s.webhooks = append(s.webhooks, webhook)
+	return webhook, nil
+}
+
+func (s *Server) updateWebhook(r *http.Request, _ string) (any, error) {
+	var req types.WebhookPayload
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	webhook, err := s.webhook(req.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhook(req); err != nil {
+		return nil, err
+	}
+
+	webhook.Name = req.Name
+	webhook.URL = req.URL
+	webhook.EventTypes = req.EventTypes
+	webhook.UpdatedAt = s.now().UTC()
+	return map[string]string{"message": "Webhook updated successfully"}, nil
+}
+
+func (s *Server) deleteWebhook(r *http.Request, id string) (any, error) {
+	if _, err := s.webhook(id); err != nil {
+		return nil, err
+	}
+	for i, webhook := range s.webhooks {
+		if webhook.WebhookID == id {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			break
+		}
+	}
+	return map[string]string{"message": "Webhook deleted successfully"}, nil
+}
+
+// webhook 返回指定 ID 的 webhook, 不存在时返回 404
+func (s *Server) webhook(id string) (*types.Webhook, error) {
+	for i := range s.webhooks {
+		if s.webhooks[i].WebhookID == id {
+			return &s.webhooks[i], nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "Webhook with id %s not found", id)
+}
+
+// validateWebhook 检查 webhook 的必填字段, 返回按字段分组的错误
+func validateWebhook(req types.WebhookPayload) error {
+	problems := make(map[string][]string)
+	if req.Name == "" {
+		problems["name"] = []string{"This field may not be blank."}
+	}
+	if u, err := url.Parse(req.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		problems["url"] = []string{"Enter a valid URL."}
+	}
+	if len(req.EventTypes) == 0 {
+		problems["event_types"] = []string{"This list may not be empty."}
+	}
+	if len(problems) > 0 {
+		return &apiError{status: http.StatusBadRequest, body: problems}
+	}
+	return nil
+}
+
+func (s *Server) addFeedback(r *http.Request, _ string) (any, error) {
+	var req types.FeedbackPayload
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if _, err := s.memory(req.MemoryID); err != nil {
+		return nil, err
+	}
+	switch req.Feedback {
+	case "", types.Positive, types.Negative, types.VeryNegative:
+	default:
+		return nil, &apiError{status: http.StatusBadRequest, body: map[string][]string{
+			"feedback": {"\"" + string(req.Feedback) + "\" is not a valid choice."},
+		}}
+	}
+
+	s.feedback = append(s.feedback, req)
+	return map[string]any{"id": s.nextID("fb"), "feedback": req.Feedback, "feedback_reason": req.FeedbackReason}, nil
+}
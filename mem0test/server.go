@@ -0,0 +1,397 @@
+// Package mem0test 提供内存中的 Mem0 API 模拟服务, 用于在没有网络和 API key 的情况下测试依赖 Mem0 的代码
+//
+// Server 实现了客户端调用的所有接口, 状态保存在内存中:
+//
+//	srv := mem0test.NewServer()
+//	defer srv.Close()
+//
+//	mem0, err := client.NewMemoryClient(client.ClientOptions{APIKey: mem0test.APIKey, Host: srv.URL})
+//
+// 与真实服务的主要区别:
+//   - 添加内存时不调用模型, infer 为 true 时每条 user 消息保存为一条内存, 为 false 时每条消息保存为一条内存
+//   - 搜索使用词频的余弦相似度, 不是向量检索
+//   - 异步添加立即完成, 返回的事件已经是 SUCCEEDED 状态
+//
+// InjectFault 可以为匹配的请求注入延迟或错误状态码, 用于测试重试和限流.
+package mem0test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// Server 默认接受的 API key 以及 ping 返回的组织, 项目和用户邮箱
+const (
+	APIKey    = "test-key"
+	OrgID     = "test-org"
+	ProjectID = "test-project"
+	UserEmail = "test@example.com"
+)
+
+// Option 定义 Server 的可选配置
+type Option func(*Server)
+
+// WithAPIKey 设置接受的 API key, 为空时接受任意 API key
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithClock 设置服务端的当前时间, 用于生成可预测的 created_at 和 updated_at
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Fault 是注入到匹配请求的故障
+type Fault struct {
+	// Method 为空时匹配所有方法
+	Method string
+	// Path 是请求路径的前缀, 为空时匹配所有路径, 例如 "/v2/memories/search/"
+	Path string
+	// Latency 是处理请求前的延迟, 客户端取消请求时提前结束
+	Latency time.Duration
+	// Status 不为 0 时不处理请求, 直接返回该状态码, 例如 429 或 500
+	Status int
+	// RetryAfter 不为 0 时在错误响应中设置 Retry-After 响应头
+	RetryAfter time.Duration
+	// Times 是故障生效的次数, 0 表示一直生效, 直到 ClearFaults
+	Times int
+}
+
+// matches 判断故障是否作用于请求
+func (f *Fault) matches(r *http.Request) bool {
+	return (f.Method == "" || strings.EqualFold(f.Method, r.Method)) && strings.HasPrefix(r.URL.Path, f.Path)
+}
+
+// Server 是内存中的 Mem0 API 模拟服务
+type Server struct {
+	// URL 是服务的地址, 用作 ClientOptions.Host
+	URL string
+
+	server *httptest.Server
+	apiKey string
+	now    func() time.Time
+
+	mu       sync.Mutex
+	seq      int
+	memories []types.Memory
+	history  map[string][]types.MemoryHistory
+	events   []types.Event
+	webhooks []types.Webhook
+	project  project
+	feedback []types.FeedbackPayload
+	faults   []*Fault
+	requests []string
+}
+
+// project 是项目设置
+type project struct {
+	instructions string
+	categories   []types.CustomCategory
+}
+
+// NewServer 启动一个新的模拟服务, 使用完后需要调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		apiKey:  APIKey,
+		now:     time.Now,
+		history: make(map[string][]types.MemoryHistory),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close 关闭服务
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Reset 清空所有内存, 历史, 事件, webhook, 反馈, 项目设置, 故障和请求记录
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memories = nil
+	s.history = make(map[string][]types.MemoryHistory)
+	s.events = nil
+	s.webhooks = nil
+	s.project = project{}
+	s.feedback = nil
+	s.faults = nil
+	s.requests = nil
+}
+
+// InjectFault 为之后匹配的请求注入故障, 多个故障匹配同一请求时使用最早注入的
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults 移除所有故障
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests 返回收到的所有请求, 格式为 "METHOD /path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// AddMemory 直接保存一条内存, 用于准备测试数据
+// 没有设置 ID, Hash, CreatedAt 和 UpdatedAt 时自动生成, 返回保存的内存
+func (s *Server) AddMemory(memory types.Memory) types.Memory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insert(memory, nil)
+}
+
+// Memories 返回所有内存, 按添加顺序排列
+func (s *Server) Memories() []types.Memory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memories := make([]types.Memory, len(s.memories))
+	for i, m := range s.memories {
+		memories[i] = clone(m)
+	}
+	return memories
+}
+
+// Feedback 返回收到的所有反馈
+func (s *Server) Feedback() []types.FeedbackPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]types.FeedbackPayload(nil), s.feedback...)
+}
+
+// apiError 是返回给客户端的错误响应
+type apiError struct {
+	status int
+	body   any
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d %v", e.status, e.body)
+}
+
+// errorf 返回 {"detail": "..."} 形式的错误响应
+func errorf(status int, format string, args ...any) error {
+	return &apiError{status: status, body: map[string]string{"detail": fmt.Sprintf(format, args...)}}
+}
+
+// handler 处理一个请求, param 是路径中的 ID
+type handler func(r *http.Request, param string) (any, error)
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 先读取请求体, 使服务端可以在延迟期间发现客户端取消了请求
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	fault := s.begin(r)
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+			}
+			writeJSON(w, fault.Status, map[string]string{"detail": fmt.Sprintf("injected fault: %s", http.StatusText(fault.Status))})
+			return
+		}
+	}
+
+	if s.apiKey != "" && r.Header.Get("Authorization") != "Token "+s.apiKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"detail": "Invalid API key"})
+		return
+	}
+
+	h, param := s.route(r.Method, r.URL.Path)
+	if h == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"detail": fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path)})
+		return
+	}
+
+	s.mu.Lock()
+	resp, err := h(r, param)
+	s.mu.Unlock()
+
+	if err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			writeJSON(w, apiErr.status, apiErr.body)
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"detail": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// begin 记录请求并返回作用于它的故障
+func (s *Server) begin(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		fault := *f
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &fault
+	}
+	return nil
+}
+
+// route 返回处理请求的函数和路径中的 ID
+func (s *Server) route(method, path string) (handler, string) {
+	routes := map[string]handler{
+		"GET /v1/ping/":              s.ping,
+		"POST /v1/memories/":         s.add,
+		"DELETE /v1/memories/":       s.deleteAll,
+		"POST /v2/memories/":         s.list,
+		"POST /v2/memories/search/":  s.search,
+		"PUT /v1/memories/batch/":    s.batchUpdate,
+		"DELETE /v1/memories/batch/": s.batchDelete,
+		"GET /v1/users/":             s.users,
+		"DELETE /v1/users/":          s.deleteUsers,
+		"GET /v1/project/":           s.getProject,
+		"PUT /v1/project/":           s.updateProject,
+		"GET /v1/webhooks/":          s.listWebhooks,
+		"PUT /v1/webhooks/":          s.updateWebhook,
+		"POST /v1/feedback/":         s.addFeedback,
+		"GET /v1/events/":            s.listEvents,
+	}
+	if h, ok := routes[method+" "+path]; ok {
+		return h, ""
+	}
+
+	for _, route := range []struct {
+		method, prefix, suffix string
+		h                      handler
+	}{
+		{"GET", "/v1/memories/", "/history/", s.getHistory},
+		{"GET", "/v1/memories/", "/", s.get},
+		{"PUT", "/v1/memories/", "/", s.update},
+		{"DELETE", "/v1/memories/", "/", s.delete},
+		{"DELETE", "/v1/users/", "/", s.deleteUser},
+		{"DELETE", "/v1/webhooks/", "/", s.deleteWebhook},
+		{"POST", "/api/v1/webhooks/projects/", "/", s.createWebhook},
+		{"GET", "/v1/event/", "/", s.getEvent},
+	} {
+		if method != route.method || !strings.HasPrefix(path, route.prefix) || !strings.HasSuffix(path, route.suffix) {
+			continue
+		}
+		param := strings.TrimSuffix(strings.TrimPrefix(path, route.prefix), route.suffix)
+		if param != "" && !strings.Contains(param, "/") {
+			return route.h, param
+		}
+	}
+	return nil, ""
+}
+
+func (s *Server) ping(r *http.Request, _ string) (any, error) {
+	return types.PingResponse{Status: "ok", OrgID: OrgID, ProjectID: ProjectID, UserEmail: UserEmail}, nil
+}
+
+// nextID 生成带前缀的 ID, 调用方需持有 mu
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%d", prefix, s.seq)
+}
+
+// decode 解码请求体
+func decode(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// page 解析 page 和 page_size 查询参数, 返回 items 中的一页和下一页的 URL
+func page[T any](s *Server, r *http.Request, items []T, defaultSize int) ([]T, string) {
+	query := r.URL.Query()
+	number, _ := strconv.Atoi(query.Get("page"))
+	if number <= 0 {
+		number = 1
+	}
+	size, _ := strconv.Atoi(query.Get("page_size"))
+	if size <= 0 {
+		size = defaultSize
+	}
+
+	start := (number - 1) * size
+	if start >= len(items) {
+		return []T{}, ""
+	}
+	end := start + size
+	if end >= len(items) {
+		return items[start:], ""
+	}
+	return items[start:end], fmt.Sprintf("%s%s?page=%d&page_size=%d", s.URL, r.URL.Path, number+1, size)
+}
+
+// clone 通过 JSON 深拷贝 v, 同时将 metadata 中的数字等统一为 JSON 解码后的类型
+func clone[T any](v T) T {
+	var out T
+	if err := remarshal(v, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// remarshal 通过 JSON 将 src 转换为 dst
+func remarshal(src, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,279 @@
+package mem0test_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/filters"
+	"github.com/bytectlgo/mem0-go/mem0test"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+func newClient(t *testing.T, srv *mem0test.Server, opts ...client.Option) *client.MemoryClient {
+	t.Helper()
+
+	mem0, err := client.NewMemoryClient(client.ClientOptions{APIKey: mem0test.APIKey, Host: srv.URL}, opts...)
+	require.NoError(t, err)
+	return mem0
+}
+
+func TestMemoryLifecycle(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	mem0 := newClient(t, srv)
+	ctx := context.Background()
+
+	ping, err := mem0.Ping(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, mem0test.ProjectID, ping.ProjectID)
+
+	infer := false
+	added, err := mem0.AddContext(ctx, []types.Message{
+		{Role: "user", Content: "Alice likes green tea"},
+		{Role: "assistant", Content: "Noted"},
+	}, types.AddOptions{UserID: "alice", Metadata: map[string]any{"source": "chat"}, Infer: &infer})
+	require.NoError(t, err)
+	require.Len(t, added, 2)
+	assert.Equal(t, types.EventTypeMemoryAdd, added[0].Event)
+
+	// 推理时只保存 user 消息, 已有的内容不会重复添加
+	added, err = mem0.AddContext(ctx, []types.Message{
+		{Role: "user", Content: "Alice likes green tea"},
+		{Role: "assistant", Content: "Anything else?"},
+	}, types.AddOptions{UserID: "alice"})
+	require.NoError(t, err)
+	assert.Empty(t, added)
+
+	memories := srv.Memories()
+	require.Len(t, memories, 2)
+	id := memories[0].ID
+
+	memory, err := mem0.GetContext(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice likes green tea", memory.Memory)
+	assert.Equal(t, map[string]any{"source": "chat"}, memory.Metadata)
+
+	_, err = mem0.UpdateContext(ctx, id, "Alice likes black tea")
+	require.NoError(t, err)
+	require.NoError(t, mem0.DeleteContext(ctx, id))
+
+	_, err = mem0.GetContext(ctx, id)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	history, err := mem0.HistoryContext(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, []types.EventType{types.EventTypeMemoryAdd, types.EventTypeMemoryUpdate, types.EventTypeMemoryDelete},
+		[]types.EventType{history[0].Event, history[1].Event, history[2].Event})
+	assert.Equal(t, "Alice likes green tea", history[1].OldMemory)
+	assert.Equal(t, "Alice likes black tea", history[1].NewMemory)
+
+	require.NoError(t, mem0.DeleteAllContext(ctx, types.DeleteAllOptions{UserID: "alice"}))
+	assert.Empty(t, srv.Memories())
+}
+
+func TestAddAndWait(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	mem0 := newClient(t, srv)
+
+	outcomes, err := mem0.AddAndWait(context.Background(), "I moved to Berlin", types.AddOptions{UserID: "alice"},
+		client.WaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	memories := outcomes.Memories()
+	require.Len(t, memories, 1)
+	assert.Equal(t, "I moved to Berlin", memories[0].Memory)
+
+	var events []types.Event
+	it := mem0.IterEvents(context.Background())
+	defer it.Close()
+	for it.Next() {
+		events = append(events, it.Value())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, events, 1)
+	payload, err := events[0].AddPayload()
+	require.NoError(t, err)
+	assert.Equal(t, "alice", payload.UserID)
+}
+
+func TestGetAllFilters(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	for _, m := range []types.Memory{
+		{Memory: "likes tea", UserID: "alice", Categories: []string{"food"}, Metadata: map[string]any{"source": "chat"}, CreatedAt: day},
+		{Memory: "works at ACME", UserID: "alice", Categories: []string{"work"}, Metadata: map[string]any{"source": "crm"}, CreatedAt: day.AddDate(0, 0, 1)},
+		{Memory: "likes coffee", UserID: "bob", Categories: []string{"food"}, CreatedAt: day.AddDate(0, 0, 2)},
+		{Memory: "answers in French", AgentID: "bot", CreatedAt: day.AddDate(0, 0, 3)},
+	} {
+		srv.AddMemory(m)
+	}
+	mem0 := newClient(t, srv)
+
+	texts := func(f filters.Filter, categories ...string) []string {
+		t.Helper()
+		memories, err := mem0.GetAllContext(context.Background(), &types.ListOptions{Filters: filters.MustBuild(f), Categories: categories})
+		require.NoError(t, err)
+		var texts []string
+		for _, m := range memories {
+			texts = append(texts, m.Memory)
+		}
+		return texts
+	}
+
+	assert.Equal(t, []string{"likes tea", "works at ACME"}, texts(filters.UserID("alice")))
+	assert.Equal(t, []string{"answers in French"}, texts(filters.AgentID("bot")))
+	assert.Equal(t, []string{"likes tea", "likes coffee"}, texts(filters.Or(filters.UserID("bob"), filters.Metadata("source", "chat"))))
+	assert.Equal(t, []string{"works at ACME"}, texts(filters.And(filters.UserID("alice"), filters.Not(filters.Categories("food")))))
+	assert.Equal(t, []string{"likes coffee", "answers in French"}, texts(filters.CreatedAfter(day.AddDate(0, 0, 2))))
+	assert.Equal(t, []string{"likes tea", "likes coffee"}, texts(filters.Any(filters.FieldUserID), "food"))
+	assert.Equal(t, []string{"works at ACME"}, texts(filters.IContains(filters.FieldKeywords, "acme")))
+
+	_, err := mem0.GetAllContext(context.Background(), &types.ListOptions{Filters: map[string]any{"owner": "alice"}})
+	assert.ErrorIs(t, err, client.ErrValidation)
+
+	var all []string
+	it := mem0.IterMemories(context.Background(), &types.ListOptions{PageSize: 3})
+	defer it.Close()
+	for it.Next() {
+		all = append(all, it.Value().Memory)
+	}
+	require.NoError(t, it.Err())
+	assert.Len(t, all, 4)
+}
+
+func TestSearch(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	for _, m := range []types.Memory{
+		{Memory: "Alice likes green tea", UserID: "alice"},
+		{Memory: "Alice drinks tea every morning with green apples", UserID: "alice"},
+		{Memory: "Alice works at ACME", UserID: "alice"},
+		{Memory: "Bob likes green tea", UserID: "bob"},
+	} {
+		srv.AddMemory(m)
+	}
+	mem0 := newClient(t, srv)
+
+	results, err := mem0.SearchContext(context.Background(), "green tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Alice likes green tea", results[0].Memory)
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	results, err = mem0.SearchContext(context.Background(), "green tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}, TopK: 1})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = mem0.SearchContext(context.Background(), "green tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}, Threshold: 0.6})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestBatchAndEntities(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	a := srv.AddMemory(types.Memory{Memory: "one", UserID: "alice"})
+	b := srv.AddMemory(types.Memory{Memory: "two", UserID: "alice"})
+	srv.AddMemory(types.Memory{Memory: "three", AgentID: "bot", RunID: "run-1"})
+	mem0 := newClient(t, srv)
+	ctx := context.Background()
+
+	require.NoError(t, mem0.BatchUpdateContext(ctx, []types.MemoryUpdateBody{{MemoryID: a.ID, Text: "uno"}, {MemoryID: b.ID, Text: "dos"}}))
+	err := mem0.BatchUpdateContext(ctx, []types.MemoryUpdateBody{{MemoryID: a.ID, Text: "eins"}, {MemoryID: "missing", Text: "x"}})
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	assert.Equal(t, "uno", srv.Memories()[0].Memory, "a failed batch must not update anything")
+
+	var entities []string
+	it := mem0.IterEntities(ctx)
+	defer it.Close()
+	for it.Next() {
+		user := it.Value()
+		entities = append(entities, user.Type+":"+user.ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"user:alice", "agent:bot", "run:run-1"}, entities)
+
+	require.NoError(t, mem0.DeleteUserContext(ctx, "bot"))
+	assert.ErrorIs(t, mem0.DeleteUserContext(ctx, "bot"), client.ErrNotFound)
+	require.NoError(t, mem0.BatchDeleteContext(ctx, []string{a.ID, b.ID}))
+	assert.Empty(t, srv.Memories())
+}
+
+func TestProjectWebhooksAndFeedback(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	mem0 := newClient(t, srv)
+	ctx := context.Background()
+
+	require.NoError(t, mem0.UpdateProjectContext(ctx, types.PromptUpdatePayload{
+		CustomInstructions: "Only store preferences",
+		CustomCategories:   []types.CustomCategory{{CategoryName: "tea", CategoryDescription: "Tea preferences"}},
+	}))
+	project, err := mem0.GetProjectContext(ctx, types.ProjectOptions{Fields: []string{"custom_categories"}})
+	require.NoError(t, err)
+	assert.Equal(t, &types.ProjectResponse{CustomCategories: []string{"tea"}}, project)
+
+	// 自定义分类用于之后添加的内存
+	memory := srv.AddMemory(types.Memory{Memory: "Likes green tea", UserID: "alice"})
+	assert.Equal(t, []string{"tea"}, memory.Categories)
+
+	payload := types.WebhookPayload{Name: "hook", URL: "https://example.com/hook", EventTypes: []types.WebhookEvent{types.MemoryAdded}}
+	webhook, err := mem0.CreateWebhookContext(ctx, mem0test.ProjectID, payload)
+	require.NoError(t, err)
+	_, err = mem0.CreateWebhookContext(ctx, mem0test.ProjectID, payload)
+	assert.ErrorIs(t, err, client.ErrDuplicateWebhook)
+
+	payload.WebhookID = webhook.WebhookID
+	payload.Name = "renamed"
+	require.NoError(t, mem0.UpdateWebhookContext(ctx, payload))
+	webhooks, err := mem0.GetWebhooksContext(ctx, mem0test.ProjectID)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, "renamed", webhooks[0].Name)
+	require.NoError(t, mem0.DeleteWebhookContext(ctx, webhook.WebhookID))
+	assert.ErrorIs(t, mem0.DeleteWebhookContext(ctx, webhook.WebhookID), client.ErrNotFound)
+
+	feedback := types.FeedbackPayload{MemoryID: memory.ID, Feedback: types.Positive}
+	require.NoError(t, mem0.FeedbackContext(ctx, feedback))
+	assert.Equal(t, []types.FeedbackPayload{feedback}, srv.Feedback())
+}
+
+func TestFaults(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err := client.NewMemoryClient(client.ClientOptions{APIKey: "wrong", Host: srv.URL})
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+
+	mem0 := newClient(t, srv, client.WithRetryPolicy(&client.RetryPolicy{
+		MaxAttempts:      3,
+		BaseBackoff:      time.Millisecond,
+		RetryStatusCodes: []int{http.StatusTooManyRequests},
+	}))
+
+	srv.InjectFault(mem0test.Fault{Method: "POST", Path: "/v2/memories/search/", Status: http.StatusTooManyRequests, Times: 2})
+	_, err = mem0.SearchContext(ctx, "tea", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /v2/memories/search/", "POST /v2/memories/search/", "POST /v2/memories/search/"}, srv.Requests()[2:])
+
+	srv.InjectFault(mem0test.Fault{Path: "/v2/", Status: http.StatusInternalServerError})
+	_, err = mem0.GetAllContext(context.Background(), nil)
+	assert.ErrorIs(t, err, client.ErrServer)
+	srv.ClearFaults()
+
+	srv.InjectFault(mem0test.Fault{Latency: time.Second})
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = mem0.GetAllContext(timeout, nil)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+}
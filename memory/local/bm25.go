@@ -0,0 +1,93 @@
+package local
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// BM25 参数, 使用常见的默认值
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// bm25 保存计算 BM25 分数所需的语料统计
+type bm25 struct {
+	docs   int
+	avgLen float64
+	df     map[string]int
+}
+
+// newBM25 统计语料中的文档数, 平均长度和每个词出现的文档数
+func newBM25(docs [][]string) *bm25 {
+	b := &bm25{docs: len(docs), df: make(map[string]int)}
+	total := 0
+	for _, terms := range docs {
+		total += len(terms)
+		seen := make(map[string]bool, len(terms))
+		for _, term := range terms {
+			if !seen[term] {
+				seen[term] = true
+				b.df[term]++
+			}
+		}
+	}
+	if b.docs > 0 {
+		b.avgLen = float64(total) / float64(b.docs)
+	}
+	return b
+}
+
+// score 返回文档对查询的 BM25 分数, 查询中重复的词只计算一次
+func (b *bm25) score(query, doc []string) float64 {
+	if len(doc) == 0 || b.avgLen == 0 {
+		return 0
+	}
+	tf := make(map[string]int, len(doc))
+	for _, term := range doc {
+		tf[term]++
+	}
+
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(len(doc))/b.avgLen)
+	seen := make(map[string]bool, len(query))
+	var score float64
+	for _, term := range query {
+		if seen[term] || tf[term] == 0 {
+			continue
+		}
+		seen[term] = true
+		df := float64(b.df[term])
+		idf := math.Log(1 + (float64(b.docs)-df+0.5)/(df+0.5))
+		f := float64(tf[term])
+		score += idf * f * (bm25K1 + 1) / (f + norm)
+	}
+	return score
+}
+
+// tokenize 将文本转换为小写的词, 以字母和数字之外的字符分隔
+// 汉字等没有空格分隔的文字按单个字符切分
+func tokenize(text string) []string {
+	var terms []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			terms = append(terms, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			flush()
+			terms = append(terms, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return terms
+}
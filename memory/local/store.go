@@ -0,0 +1,579 @@
+// Package local 提供保存在本地文件中的嵌入式内存存储, 用于离线开发和边缘部署
+//
+// Store 实现 memory.Memory 接口, 与托管 API 的主要差别:
+//   - 不调用 LLM. 推理模式 (默认) 把每条 user 消息保存为一条内存, 跳过同一实体下已有的相同内容;
+//     Infer 为 false 时原样保存所有消息.
+//   - Search 使用 BM25 关键词相关度, Score 是相对于最佳结果的分数, 范围为 (0, 1].
+//   - Filters 在本地求值, 支持的字段和运算符见 filters.Match.
+//
+// 每次修改后整个存储被写入文件, 适合单个进程使用的中小规模数据.
+package local
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/filters"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// fileVersion 是存储文件格式的版本
+const fileVersion = 1
+
+// defaultTopK 是 Search 默认返回的条目数
+const defaultTopK = 10
+
+// Option 配置 Store
+type Option func(*Store)
+
+// WithClock 设置 Store 使用的时钟, 默认为 time.Now
+func WithClock(now func() time.Time) Option {
+	return func(s *Store) {
+		s.now = now
+	}
+}
+
+// storeFile 是存储文件的内容
+type storeFile struct {
+	Version  int                              `json:"version"`
+	Memories []types.Memory                   `json:"memories"`
+	History  map[string][]types.MemoryHistory `json:"history"`
+}
+
+// Store 是本地内存存储, 可以并发使用
+type Store struct {
+	path string
+	now  func() time.Time
+
+	mu   sync.RWMutex
+	data storeFile
+	// terms 缓存每条内存文本的分词结果
+	terms map[string][]string
+}
+
+// Open 打开 path 指向的存储文件, 文件不存在时在第一次写入时创建. path 为空时只保存在进程内存中
+func Open(path string, opts ...Option) (*Store, error) {
+	s := &Store{
+		path:  path,
+		now:   time.Now,
+		data:  storeFile{Version: fileVersion, Memories: []types.Memory{}, History: map[string][]types.MemoryHistory{}},
+		terms: make(map[string][]string),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if path != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range s.data.Memories {
+		s.terms[m.ID] = tokenize(m.Memory)
+	}
+	return s, nil
+}
+
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read memory store")
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return errors.Wrapf(err, "failed to parse memory store %s", s.path)
+	}
+	if file.Version != fileVersion {
+		return errors.Errorf("unsupported memory store version %d in %s", file.Version, s.path)
+	}
+	if file.Memories == nil {
+		file.Memories = []types.Memory{}
+	}
+	if file.History == nil {
+		file.History = map[string][]types.MemoryHistory{}
+	}
+	s.data = file
+	return nil
+}
+
+// save 将存储写入临时文件后替换原文件, 调用方需持有写锁
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode memory store")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return errors.Wrap(err, "failed to create memory store directory")
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return errors.Wrap(err, "failed to write memory store")
+	}
+	return errors.Wrap(os.Rename(tmp, s.path), "failed to replace memory store")
+}
+
+// snapshot 是修改前的存储状态, 写入文件失败时用于回滚
+type snapshot struct {
+	memories []types.Memory
+	history  map[string][]types.MemoryHistory
+	terms    map[string][]string
+}
+
+// snapshot 保存当前状态, 调用方需持有写锁
+// 内存条目被复制, 历史和分词结果只会追加或整体替换, 复制映射即可
+func (s *Store) snapshot() snapshot {
+	snap := snapshot{
+		memories: append([]types.Memory(nil), s.data.Memories...),
+		history:  make(map[string][]types.MemoryHistory, len(s.data.History)),
+		terms:    make(map[string][]string, len(s.terms)),
+	}
+	for id, history := range s.data.History {
+		snap.history[id] = history
+	}
+	for id, terms := range s.terms {
+		snap.terms[id] = terms
+	}
+	return snap
+}
+
+// commit 写入文件, 失败时恢复到 snap, 使返回错误的修改不留下任何痕迹. 调用方需持有写锁
+func (s *Store) commit(snap snapshot) error {
+	if err := s.save(); err != nil {
+		s.data.Memories = snap.memories
+		s.data.History = snap.history
+		s.terms = snap.terms
+		return err
+	}
+	return nil
+}
+
+// Add 保存消息中的内存并返回新增的内存, messages 可以是 string, []string, types.Message 或 []types.Message
+func (s *Store) Add(ctx context.Context, messages any, options types.AddOptions) ([]types.Memory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	input, err := toMessages(messages)
+	if err != nil {
+		return nil, err
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := s.snapshot()
+
+	now := s.now().UTC()
+	createdAt := now
+	if options.Timestamp > 0 {
+		createdAt = time.Unix(options.Timestamp, 0).UTC()
+	}
+
+	infer := options.Infer == nil || *options.Infer
+	added := []types.Memory{}
+	for _, message := range input {
+		text := strings.TrimSpace(message.Content)
+		if text == "" || (infer && message.Role != "user") {
+			continue
+		}
+		memory := types.Memory{
+			ID:        newID(),
+			Memory:    text,
+			Hash:      hash(text),
+			UserID:    options.UserID,
+			AgentID:   options.AgentID,
+			AppID:     options.AppID,
+			RunID:     options.RunID,
+			Metadata:  cloneMetadata(options.Metadata),
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+		// 推理时相同实体下已有的内容不会重复添加
+		if infer && s.duplicate(&memory) {
+			continue
+		}
+
+		s.data.Memories = append(s.data.Memories, memory)
+		s.terms[memory.ID] = tokenize(text)
+		s.record(&memory, types.EventTypeMemoryAdd, input, "", text, now)
+
+		memory = clone(memory)
+		memory.Event = types.EventTypeMemoryAdd
+		added = append(added, memory)
+	}
+
+	if len(added) == 0 {
+		return added, nil
+	}
+	if err := s.commit(snap); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// duplicate 判断相同实体下是否已有相同内容的内存
+func (s *Store) duplicate(m *types.Memory) bool {
+	for i := range s.data.Memories {
+		other := &s.data.Memories[i]
+		if other.Hash == m.Hash && other.UserID == m.UserID && other.AgentID == m.AgentID &&
+			other.AppID == m.AppID && other.RunID == m.RunID {
+			return true
+		}
+	}
+	return false
+}
+
+// record 追加一条历史记录, 调用方需持有写锁
+func (s *Store) record(m *types.Memory, event types.EventType, input []types.Message, oldText, newText string, now time.Time) {
+	s.data.History[m.ID] = append(s.data.History[m.ID], types.MemoryHistory{
+		ID:         newID(),
+		MemoryID:   m.ID,
+		Input:      input,
+		OldMemory:  oldText,
+		NewMemory:  newText,
+		UserID:     m.UserID,
+		Categories: m.Categories,
+		Event:      event,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
+
+// Search 按 BM25 相关度返回满足过滤条件的内存
+// Filters 为空时使用 UserID, AgentID, AppID 和 RunID 过滤, 与托管 API 一样两者不能都为空. TopK 默认为 10,
+// Threshold 大于 0 时只返回相对分数不低于它的内存
+func (s *Store) Search(ctx context.Context, query string, options *types.QueryOptions) ([]types.Memory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &types.QueryOptions{}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	conds := options.Filters
+	if len(conds) == 0 {
+		conds = entityFilters(options.UserID, options.AgentID, options.AppID, options.RunID)
+	}
+	if len(conds) == 0 {
+		return nil, errors.Wrap(client.ErrValidation, "filters or one of user_id, agent_id, app_id or run_id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates, err := s.filter(conds, options.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([][]string, len(s.data.Memories))
+	for i, m := range s.data.Memories {
+		docs[i] = s.terms[m.ID]
+	}
+	scorer := newBM25(docs)
+	queryTerms := tokenize(query)
+
+	results := []types.Memory{}
+	for _, m := range candidates {
+		if score := scorer.score(queryTerms, s.terms[m.ID]); score > 0 {
+			m.Score = score
+			results = append(results, m)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	best := results[0].Score
+	topK := options.TopK
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	kept := results[:0]
+	for _, m := range results {
+		m.Score /= best
+		if m.Score < options.Threshold || len(kept) == topK {
+			break
+		}
+		kept = append(kept, m)
+	}
+	return kept, nil
+}
+
+// GetAll 按创建时间倒序返回满足过滤条件的内存, Page 大于 0 时分页, PageSize 默认为 100
+func (s *Store) GetAll(ctx context.Context, options *types.ListOptions) ([]types.Memory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &types.ListOptions{}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	memories, err := s.filter(options.Filters, options.Categories)
+	if err != nil {
+		return nil, err
+	}
+	// 创建时间相同的内存按添加顺序倒序排列
+	for i, j := 0, len(memories)-1; i < j; i, j = i+1, j-1 {
+		memories[i], memories[j] = memories[j], memories[i]
+	}
+	sort.SliceStable(memories, func(i, j int) bool {
+		return memories[i].CreatedAt.After(memories[j].CreatedAt)
+	})
+
+	if options.Page <= 0 {
+		return memories, nil
+	}
+	size := options.PageSize
+	if size <= 0 {
+		size = 100
+	}
+	start := (options.Page - 1) * size
+	if start >= len(memories) {
+		return []types.Memory{}, nil
+	}
+	return memories[start:min(start+size, len(memories))], nil
+}
+
+// filter 返回满足过滤条件并属于任一分类的内存副本, 调用方需持有读锁
+func (s *Store) filter(conds map[string]any, categories []string) ([]types.Memory, error) {
+	memories := []types.Memory{}
+	for i := range s.data.Memories {
+		m := &s.data.Memories[i]
+		if len(conds) > 0 {
+			ok, err := filters.Match(m, conds)
+			if err != nil {
+				return nil, errors.Wrapf(client.ErrValidation, "invalid filters: %v", err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		if len(categories) > 0 && !hasCategory(m.Categories, categories) {
+			continue
+		}
+		memories = append(memories, clone(*m))
+	}
+	return memories, nil
+}
+
+// Get 返回指定 ID 的内存
+func (s *Store) Get(ctx context.Context, memoryID string) (*types.Memory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, err := s.index(memoryID)
+	if err != nil {
+		return nil, err
+	}
+	memory := clone(s.data.Memories[i])
+	return &memory, nil
+}
+
+// Update 修改内存的文本并记录历史, 返回修改后的内存
+func (s *Store) Update(ctx context.Context, memoryID string, text string) ([]types.Memory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.Wrap(client.ErrValidation, "text is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.index(memoryID)
+	if err != nil {
+		return nil, err
+	}
+	snap := s.snapshot()
+	now := s.now().UTC()
+	m := &s.data.Memories[i]
+	old := m.Memory
+	m.Memory = text
+	m.Hash = hash(text)
+	m.UpdatedAt = now
+	s.terms[m.ID] = tokenize(text)
+	s.record(m, types.EventTypeMemoryUpdate, []types.Message{{Role: "user", Content: text}}, old, text, now)
+
+	updated := clone(*m)
+	updated.Event = types.EventTypeMemoryUpdate
+	if err := s.commit(snap); err != nil {
+		return nil, err
+	}
+	return []types.Memory{updated}, nil
+}
+
+// Delete 删除内存, 历史记录被保留
+func (s *Store) Delete(ctx context.Context, memoryID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.index(memoryID)
+	if err != nil {
+		return err
+	}
+	snap := s.snapshot()
+	m := s.data.Memories[i]
+	s.record(&m, types.EventTypeMemoryDelete, nil, m.Memory, "", s.now().UTC())
+	s.data.Memories = append(s.data.Memories[:i], s.data.Memories[i+1:]...)
+	delete(s.terms, memoryID)
+	return s.commit(snap)
+}
+
+// History 返回内存的修改历史, 按时间顺序排列. 已删除的内存仍然可以查询历史
+func (s *Store) History(ctx context.Context, memoryID string) ([]types.MemoryHistory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history, ok := s.data.History[memoryID]
+	if !ok {
+		return nil, notFound(memoryID)
+	}
+	return append([]types.MemoryHistory(nil), history...), nil
+}
+
+// index 返回内存在 data.Memories 中的位置
+func (s *Store) index(memoryID string) (int, error) {
+	for i := range s.data.Memories {
+		if s.data.Memories[i].ID == memoryID {
+			return i, nil
+		}
+	}
+	return -1, notFound(memoryID)
+}
+
+func notFound(memoryID string) error {
+	return errors.Wrapf(client.ErrNotFound, "memory %s not found", memoryID)
+}
+
+// toMessages 将 Add 支持的消息类型转换为 []types.Message
+func toMessages(messages any) ([]types.Message, error) {
+	switch m := messages.(type) {
+	case string:
+		return []types.Message{{Role: "user", Content: m}}, nil
+	case []string:
+		out := make([]types.Message, len(m))
+		for i, content := range m {
+			out[i] = types.Message{Role: "user", Content: content}
+		}
+		return out, nil
+	case types.Message:
+		return []types.Message{m}, nil
+	case []types.Message:
+		return m, nil
+	}
+	return nil, errors.Wrap(client.ErrValidation, "invalid messages type")
+}
+
+// entityFilters 将 v1 实体 ID 转换为 v2 过滤条件, 全部为空时返回 nil
+func entityFilters(userID, agentID, appID, runID string) map[string]any {
+	conds := map[string]any{}
+	for field, id := range map[string]string{
+		filters.FieldUserID:  userID,
+		filters.FieldAgentID: agentID,
+		filters.FieldAppID:   appID,
+		filters.FieldRunID:   runID,
+	} {
+		if id != "" {
+			conds[field] = id
+		}
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	return conds
+}
+
+func hasCategory(categories, wanted []string) bool {
+	for _, category := range categories {
+		for _, w := range wanted {
+			if category == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clone 返回内存的副本, 不与存储共享 Categories 和 Metadata
+func clone(m types.Memory) types.Memory {
+	m.Categories = append([]string(nil), m.Categories...)
+	m.Metadata = cloneMetadata(m.Metadata)
+	return m
+}
+
+func cloneMetadata(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+	out := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		out[key] = value
+	}
+	return out
+}
+
+// hash 与托管 API 一样使用文本的 MD5
+func hash(text string) string {
+	sum := md5.Sum([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// newID 返回随机的 UUID v4
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
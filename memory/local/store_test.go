@@ -0,0 +1,206 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/filters"
+	"github.com/bytectlgo/mem0-go/memory/local"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// clock 每次调用前进一秒, 使创建时间有确定的顺序
+func clock() func() time.Time {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func TestStoreLifecycle(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "memories.json")
+	store, err := local.Open(path, local.WithClock(clock()))
+	require.NoError(t, err)
+
+	added, err := store.Add(ctx, []types.Message{
+		{Role: "user", Content: "Alice likes green tea"},
+		{Role: "assistant", Content: "Noted"},
+		{Role: "user", Content: "Alice is allergic to peanuts"},
+	}, types.AddOptions{UserID: "alice", Metadata: map[string]any{"source": "chat"}})
+	require.NoError(t, err)
+	require.Len(t, added, 2)
+	assert.Equal(t, types.EventTypeMemoryAdd, added[0].Event)
+
+	// 推理时相同实体下已有的内容不会重复添加, 关闭推理时保存所有消息
+	added, err = store.Add(ctx, "Alice likes green tea", types.AddOptions{UserID: "alice"})
+	require.NoError(t, err)
+	assert.Empty(t, added)
+	infer := false
+	_, err = store.Add(ctx, []string{"Bob drinks black coffee", "Bob likes tea"}, types.AddOptions{
+		UserID: "bob", Infer: &infer, Metadata: map[string]any{"source": "email"},
+	})
+	require.NoError(t, err)
+
+	_, err = store.Add(ctx, "no entity", types.AddOptions{})
+	assert.True(t, errors.Is(err, client.ErrValidation))
+
+	all, err := store.GetAll(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, "Bob likes tea", all[0].Memory)
+
+	page, err := store.GetAll(ctx, &types.ListOptions{
+		Filters:  filters.MustBuild(filters.Metadata("source", "chat")),
+		Page:     2,
+		PageSize: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "Alice likes green tea", page[0].Memory)
+	id := page[0].ID
+
+	updated, err := store.Update(ctx, id, "Alice likes jasmine tea")
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "Alice likes jasmine tea", updated[0].Memory)
+
+	// 重新打开后数据和历史仍然存在
+	store, err = local.Open(path)
+	require.NoError(t, err)
+	memory, err := store.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice likes jasmine tea", memory.Memory)
+	assert.Equal(t, map[string]any{"source": "chat"}, memory.Metadata)
+
+	require.NoError(t, store.Delete(ctx, id))
+	_, err = store.Get(ctx, id)
+	assert.True(t, errors.Is(err, client.ErrNotFound))
+	assert.True(t, errors.Is(store.Delete(ctx, id), client.ErrNotFound))
+
+	history, err := store.History(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, types.EventTypeMemoryAdd, history[0].Event)
+	assert.Equal(t, "Alice likes green tea", history[1].OldMemory)
+	assert.Equal(t, "Alice likes jasmine tea", history[1].NewMemory)
+	assert.Equal(t, types.EventTypeMemoryDelete, history[2].Event)
+
+	_, err = store.History(ctx, "missing")
+	assert.True(t, errors.Is(err, client.ErrNotFound))
+}
+
+func TestStoreSearch(t *testing.T) {
+	ctx := context.Background()
+	store, err := local.Open("")
+	require.NoError(t, err)
+
+	infer := false
+	_, err = store.Add(ctx, []string{
+		"Alice likes green tea in the morning",
+		"Alice drinks tea, tea and more tea",
+		"Alice plays tennis on weekends",
+		"爱丽丝喜欢喝绿茶",
+	}, types.AddOptions{UserID: "alice", Infer: &infer})
+	require.NoError(t, err)
+	_, err = store.Add(ctx, "Bob likes green tea", types.AddOptions{UserID: "bob", Metadata: map[string]any{"rating": 5}})
+	require.NoError(t, err)
+
+	results, err := store.Search(ctx, "green tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Alice likes green tea in the morning", results[0].Memory)
+	assert.Equal(t, 1.0, results[0].Score)
+	assert.Less(t, results[1].Score, 1.0)
+
+	results, err = store.Search(ctx, "green tea", &types.QueryOptions{UserID: "alice", TopK: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	results, err = store.Search(ctx, "green tea", &types.QueryOptions{UserID: "alice", Threshold: 0.99})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	results, err = store.Search(ctx, "绿茶", &types.QueryOptions{UserID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "爱丽丝喜欢喝绿茶", results[0].Memory)
+
+	results, err = store.Search(ctx, "tea", &types.QueryOptions{
+		Filters: map[string]any{"metadata": map[string]any{"rating": 5}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "bob", results[0].UserID)
+
+	_, err = store.Search(ctx, "tea", &types.QueryOptions{Filters: map[string]any{"score": 1}})
+	assert.True(t, errors.Is(err, client.ErrValidation))
+}
+
+func TestStoreRollbackOnSaveFailure(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "memories.json")
+	store, err := local.Open(path)
+	require.NoError(t, err)
+	added, err := store.Add(ctx, "Alice likes green tea", types.AddOptions{UserID: "alice"})
+	require.NoError(t, err)
+	id := added[0].ID
+
+	// 临时文件的位置被目录占用, 之后的写入都会失败
+	require.NoError(t, os.Mkdir(path+".tmp", 0o700))
+
+	_, err = store.Add(ctx, "Alice plays tennis", types.AddOptions{UserID: "alice"})
+	require.Error(t, err)
+	_, err = store.Update(ctx, id, "Alice likes jasmine tea")
+	require.Error(t, err)
+	require.Error(t, store.Delete(ctx, id))
+
+	all, err := store.GetAll(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "Alice likes green tea", all[0].Memory)
+	history, err := store.History(ctx, id)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+	results, err := store.Search(ctx, "tennis", &types.QueryOptions{UserID: "alice"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// 写入恢复后重试不会产生重复的内存
+	require.NoError(t, os.Remove(path+".tmp"))
+	_, err = store.Add(ctx, "Alice plays tennis", types.AddOptions{UserID: "alice"})
+	require.NoError(t, err)
+	all, err = store.GetAll(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestStoreValidation(t *testing.T) {
+	ctx := context.Background()
+	store, err := local.Open("")
+	require.NoError(t, err)
+
+	for _, options := range []*types.QueryOptions{
+		nil,
+		{},
+		{UserID: "alice", Threshold: 5},
+		{UserID: "alice", TopK: -1},
+		{Filters: map[string]any{"user_id": "alice", "agent_id": "bot"}},
+	} {
+		_, err := store.Search(ctx, "tea", options)
+		assert.True(t, errors.Is(err, client.ErrValidation), "%+v", options)
+	}
+	_, err = store.GetAll(ctx, &types.ListOptions{PageSize: -1})
+	assert.True(t, errors.Is(err, client.ErrValidation))
+	_, err = store.Add(ctx, "tea", types.AddOptions{UserID: "alice", Timestamp: -1})
+	assert.True(t, errors.Is(err, client.ErrValidation))
+}
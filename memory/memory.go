@@ -0,0 +1,126 @@
+// Package memory 定义与后端无关的 Memory 接口
+//
+// 应用代码依赖 Memory 接口, 通过配置选择托管的 Mem0 API 或本地嵌入式存储:
+//
+//	mem, err := memory.Open(ctx, memory.ConfigFromEnv())
+//	results, err := mem.Search(ctx, "tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}})
+//
+// 两个后端返回的错误都可以用 errors.Is 与 client.ErrNotFound 和 client.ErrValidation 比较.
+package memory
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/memory/local"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// Memory 是内存的增删改查和搜索接口
+type Memory interface {
+	// Add 保存消息中的内存, messages 可以是 string, []string, types.Message 或 []types.Message
+	Add(ctx context.Context, messages any, options types.AddOptions) ([]types.Memory, error)
+	Search(ctx context.Context, query string, options *types.QueryOptions) ([]types.Memory, error)
+	GetAll(ctx context.Context, options *types.ListOptions) ([]types.Memory, error)
+	Get(ctx context.Context, memoryID string) (*types.Memory, error)
+	Update(ctx context.Context, memoryID string, text string) ([]types.Memory, error)
+	Delete(ctx context.Context, memoryID string) error
+	History(ctx context.Context, memoryID string) ([]types.MemoryHistory, error)
+}
+
+var (
+	_ Memory = (*hosted)(nil)
+	_ Memory = (*local.Store)(nil)
+)
+
+// Backend 是 Memory 的实现
+type Backend string
+
+const (
+	// BackendHosted 使用托管的 Mem0 API
+	BackendHosted Backend = "hosted"
+	// BackendLocal 使用本地文件保存内存, 不访问网络
+	BackendLocal Backend = "local"
+)
+
+// Config 配置 Open
+type Config struct {
+	// Backend 为空时使用 BackendHosted
+	Backend Backend
+	// Client 和 ClientOpts 用于托管 API
+	Client     client.ClientOptions
+	ClientOpts []client.Option
+	// Path 是本地存储的文件, 为空时只保存在进程内存中
+	Path string
+}
+
+// ConfigFromEnv 从环境变量读取配置: MEM0_BACKEND, MEM0_API_KEY, MEM0_HOST 和 MEM0_LOCAL_PATH
+func ConfigFromEnv() Config {
+	return Config{
+		Backend: Backend(os.Getenv("MEM0_BACKEND")),
+		Client: client.ClientOptions{
+			APIKey: os.Getenv("MEM0_API_KEY"),
+			Host:   os.Getenv("MEM0_HOST"),
+		},
+		Path: os.Getenv("MEM0_LOCAL_PATH"),
+	}
+}
+
+// Open 按配置创建 Memory
+func Open(ctx context.Context, cfg Config) (Memory, error) {
+	switch cfg.Backend {
+	case "", BackendHosted:
+		c, err := client.NewMemoryClientContext(ctx, cfg.Client, cfg.ClientOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return Hosted(c), nil
+	case BackendLocal:
+		store, err := local.Open(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	return nil, errors.Errorf("unknown memory backend %q, expected %s or %s", cfg.Backend, BackendHosted, BackendLocal)
+}
+
+// Hosted 将托管 API 的客户端适配为 Memory
+func Hosted(c *client.MemoryClient) Memory {
+	return &hosted{c: c}
+}
+
+type hosted struct {
+	c *client.MemoryClient
+}
+
+func (h *hosted) Add(ctx context.Context, messages any, options types.AddOptions) ([]types.Memory, error) {
+	return h.c.AddContext(ctx, messages, options)
+}
+
+func (h *hosted) Search(ctx context.Context, query string, options *types.QueryOptions) ([]types.Memory, error) {
+	return h.c.SearchContext(ctx, query, options)
+}
+
+func (h *hosted) GetAll(ctx context.Context, options *types.ListOptions) ([]types.Memory, error) {
+	return h.c.GetAllContext(ctx, options)
+}
+
+func (h *hosted) Get(ctx context.Context, memoryID string) (*types.Memory, error) {
+	return h.c.GetContext(ctx, memoryID)
+}
+
+func (h *hosted) Update(ctx context.Context, memoryID string, text string) ([]types.Memory, error) {
+	return h.c.UpdateContext(ctx, memoryID, text)
+}
+
+func (h *hosted) Delete(ctx context.Context, memoryID string) error {
+	return h.c.DeleteContext(ctx, memoryID)
+}
+
+func (h *hosted) History(ctx context.Context, memoryID string) ([]types.MemoryHistory, error) {
+	return h.c.HistoryContext(ctx, memoryID)
+}
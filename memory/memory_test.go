@@ -0,0 +1,64 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/mem0test"
+	"github.com/bytectlgo/mem0-go/memory"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// TestBackends 对两个后端执行相同的调用, 只有配置不同
+func TestBackends(t *testing.T) {
+	srv := mem0test.NewServer()
+	defer srv.Close()
+
+	configs := map[string]memory.Config{
+		"hosted": {Client: client.ClientOptions{APIKey: mem0test.APIKey, Host: srv.URL}},
+		"local":  {Backend: memory.BackendLocal, Path: t.TempDir() + "/memories.json"},
+	}
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			mem, err := memory.Open(ctx, cfg)
+			require.NoError(t, err)
+
+			added, err := mem.Add(ctx, "Alice likes green tea", types.AddOptions{UserID: "alice", Metadata: map[string]any{"source": "chat"}})
+			require.NoError(t, err)
+			require.Len(t, added, 1)
+			id := added[0].ID
+
+			results, err := mem.Search(ctx, "green tea", &types.QueryOptions{Filters: map[string]any{"user_id": "alice"}})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, id, results[0].ID)
+
+			all, err := mem.GetAll(ctx, &types.ListOptions{Filters: map[string]any{"metadata": map[string]any{"source": "chat"}}})
+			require.NoError(t, err)
+			require.Len(t, all, 1)
+
+			_, err = mem.Update(ctx, id, "Alice likes jasmine tea")
+			require.NoError(t, err)
+			got, err := mem.Get(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, "Alice likes jasmine tea", got.Memory)
+
+			require.NoError(t, mem.Delete(ctx, id))
+			_, err = mem.Get(ctx, id)
+			assert.True(t, errors.Is(err, client.ErrNotFound))
+
+			history, err := mem.History(ctx, id)
+			require.NoError(t, err)
+			assert.Len(t, history, 3)
+		})
+	}
+
+	_, err := memory.Open(context.Background(), memory.Config{Backend: "sqlite"})
+	require.Error(t, err)
+}
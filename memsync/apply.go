@@ -0,0 +1,179 @@
+package memsync
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// ApplyOptions 定义如何将 Plan 应用到目标
+type ApplyOptions struct {
+	// Prune 删除只存在于目标的内存, 默认只报告不删除
+	Prune bool
+	// Infer 让服务端从源端的文本中提取内存, 默认原样保存 (Infer=false), 使两端的文本一致
+	Infer bool
+	// Workers 是同时添加的内存数, 默认为 4
+	Workers int
+	// Batch 定义批量更新和删除的分块
+	Batch client.BatchOptions
+}
+
+// ErrNotApplied 是没有确认应用成功的差异的错误, 例如批量请求没有返回对应条目的结果
+var ErrNotApplied = errors.New("change was not applied")
+
+// ChangeResult 是一条差异的应用结果
+type ChangeResult struct {
+	Change Change
+	// Err 为 nil 表示已经确认应用, 没有确认时为 ErrNotApplied 或请求的错误
+	Err error
+}
+
+// ApplyReport 是 Apply 的结果, 不包括 Skipped 的差异和未启用 Prune 时的 Removed
+type ApplyReport struct {
+	Results []ChangeResult
+}
+
+// Applied 返回某种已经应用的差异的数量
+func (r *ApplyReport) Applied(kind ChangeKind) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Change.Kind == kind && result.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed 返回应用失败的差异
+func (r *ApplyReport) Failed() []ChangeResult {
+	var failed []ChangeResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err 在有差异应用失败时返回包装了第一个失败原因的错误, 否则返回 nil
+func (r *ApplyReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.Wrapf(failed[0].Err, "%d of %d changes failed", len(failed), len(r.Results))
+}
+
+// Apply 将 plan 应用到 target:
+//   - Added 使用 Add 添加, 目标范围限定了实体时使用目标的实体, 否则使用源端内存的实体
+//   - 只有文本不同的 Changed 使用 BatchUpdate 更新
+//   - metadata 不同的 Changed 先添加新内存, 成功后删除旧内存, 因为 API 不能更新 metadata
+//   - Removed 只在 Prune 时使用 BatchDelete 删除
+//
+// 单条差异失败不会中止同步, 返回的错误与 ApplyReport.Err 相同
+func Apply(ctx context.Context, target *client.MemoryClient, plan *Plan, opts ApplyOptions) (*ApplyReport, error) {
+	report := &ApplyReport{}
+
+	var (
+		adds     []int // report.Results 的下标
+		items    []client.IngestItem
+		updates  []int
+		bodies   []types.MemoryUpdateBody
+		replaced = make(map[int]bool) // 添加成功后需要删除旧内存的 report.Results 下标
+		deletes  []int
+	)
+	for _, change := range plan.Changes {
+		if change.Skipped || (change.Kind == Removed && !opts.Prune) {
+			continue
+		}
+		i := len(report.Results)
+		report.Results = append(report.Results, ChangeResult{Change: change, Err: ErrNotApplied})
+
+		switch {
+		case change.Kind == Added || (change.Kind == Changed && change.MetadataChanged):
+			adds = append(adds, i)
+			items = append(items, client.IngestItem{
+				Messages: change.Source.Memory,
+				Options:  addOptions(change.Source, plan.Target, opts.Infer),
+			})
+			if change.Kind == Changed {
+				replaced[i] = true
+			}
+		case change.Kind == Changed:
+			updates = append(updates, i)
+			bodies = append(bodies, types.MemoryUpdateBody{MemoryID: change.Target.ID, Text: change.Source.Memory})
+		case change.Kind == Removed:
+			deletes = append(deletes, i)
+		}
+	}
+
+	for result := range target.IngestSlice(ctx, items, client.IngestOptions{Workers: opts.Workers}) {
+		i := adds[result.Index]
+		switch {
+		case result.Err != nil:
+			report.Results[i].Err = result.Err
+		case replaced[i]:
+			// 删除旧内存成功后才算应用
+			deletes = append(deletes, i)
+		default:
+			report.Results[i].Err = nil
+		}
+	}
+
+	if len(bodies) > 0 {
+		batch, err := target.BatchUpdateWithOptions(ctx, bodies, opts.Batch)
+		for j, i := range updates {
+			report.Results[i].Err = batchItemErr(batch, j, err)
+		}
+	}
+
+	if len(deletes) > 0 {
+		ids := make([]string, len(deletes))
+		for j, i := range deletes {
+			ids[j] = report.Results[i].Change.Target.ID
+		}
+		batch, err := target.BatchDeleteWithOptions(ctx, ids, opts.Batch)
+		for j, i := range deletes {
+			report.Results[i].Err = batchItemErr(batch, j, err)
+			if report.Results[i].Err != nil {
+				report.Results[i].Err = errors.Wrapf(report.Results[i].Err, "failed to delete memory %s", ids[j])
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, report.Err()
+}
+
+// batchItemErr 返回批量操作第 j 个条目的错误, 没有该条目的结果时返回整个操作的错误 err
+func batchItemErr(batch *client.BatchReport, j int, err error) error {
+	if batch != nil && j < len(batch.Items) {
+		return batch.Items[j].Err
+	}
+	if err != nil {
+		return err
+	}
+	return ErrNotApplied
+}
+
+// addOptions 返回添加 memory 到目标范围的选项
+func addOptions(memory *types.Memory, target Scope, infer bool) types.AddOptions {
+	options := types.AddOptions{Metadata: memory.Metadata, Infer: &infer}
+	if target.hasEntity() {
+		options.UserID = target.UserID
+		options.AgentID = target.AgentID
+		options.AppID = target.AppID
+		options.RunID = target.RunID
+	} else {
+		options.UserID = memory.UserID
+		options.AgentID = memory.AgentID
+		options.AppID = memory.AppID
+		options.RunID = memory.RunID
+	}
+	return options
+}
@@ -0,0 +1,286 @@
+// Package memsync 比较两个 Mem0 项目或两个实体范围中的内存, 并将差异单向同步到目标
+//
+// 同步分为两步: Diff 生成 Plan, 可以检查或保存为 JSON; Apply 将 Plan 应用到目标:
+//
+//	plan, err := memsync.Diff(ctx,
+//		memsync.Side{Client: staging, Scope: memsync.Scope{UserID: "alice"}},
+//		memsync.Side{Client: prod, Scope: memsync.Scope{UserID: "alice"}},
+//		memsync.Options{Conflict: memsync.SourceWins})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	report, err := memsync.Apply(ctx, prod, plan, memsync.ApplyOptions{})
+package memsync
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// Scope 定义一端参与比较的内存
+type Scope struct {
+	UserID  string `json:"user_id,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+	AppID   string `json:"app_id,omitempty"`
+	RunID   string `json:"run_id,omitempty"`
+	// Filters 可选, 与实体 ID 一起构成 GetAll 的 v2 过滤条件
+	Filters map[string]any `json:"filters,omitempty"`
+}
+
+// hasEntity 判断范围是否限定了实体
+func (s Scope) hasEntity() bool {
+	return s.UserID != "" || s.AgentID != "" || s.AppID != "" || s.RunID != ""
+}
+
+// listOptions 返回遍历范围内内存的选项
+func (s Scope) listOptions() *types.ListOptions {
+	filters := make(map[string]any, len(s.Filters)+4)
+	for k, v := range s.Filters {
+		filters[k] = v
+	}
+	for key, value := range map[string]string{
+		"user_id":  s.UserID,
+		"agent_id": s.AgentID,
+		"app_id":   s.AppID,
+		"run_id":   s.RunID,
+	} {
+		if value != "" {
+			filters[key] = value
+		}
+	}
+	if len(filters) == 0 {
+		filters = nil
+	}
+	return &types.ListOptions{Filters: filters}
+}
+
+// Side 是同步的一端
+type Side struct {
+	Client *client.MemoryClient
+	Scope  Scope
+}
+
+// ConflictPolicy 定义两端都存在但内容不同的内存如何处理
+type ConflictPolicy int
+
+const (
+	// SourceWins 使用源端的内容覆盖目标
+	SourceWins ConflictPolicy = iota
+	// TargetWins 保留目标的内容, 变更只在 Plan 中报告
+	TargetWins
+	// NewerWins 比较两端的更新时间 (没有时使用创建时间), 源端更新时覆盖目标
+	NewerWins
+)
+
+func (p ConflictPolicy) String() string {
+	switch p {
+	case SourceWins:
+		return "source"
+	case TargetWins:
+		return "target"
+	case NewerWins:
+		return "newer"
+	}
+	return fmt.Sprintf("ConflictPolicy(%d)", int(p))
+}
+
+// ParseConflictPolicy 解析 source, target 或 newer
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	for _, p := range []ConflictPolicy{SourceWins, TargetWins, NewerWins} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, errors.Errorf("unknown conflict policy %q (want source, target or newer)", s)
+}
+
+// Options 定义如何比较两端的内存
+type Options struct {
+	// MatchKey 是标识同一内存的 metadata 键, 例如 "source_id"; 为空或内存没有该键时按文本匹配
+	// 按文本匹配时, 文本的修改表现为一个删除和一个新增
+	MatchKey string `json:"match_key,omitempty"`
+	// IgnoreMetadata 是比较时忽略的 metadata 键, 例如时间戳等易变字段
+	IgnoreMetadata []string       `json:"ignore_metadata,omitempty"`
+	Conflict       ConflictPolicy `json:"conflict"`
+}
+
+// ChangeKind 是差异的类型
+type ChangeKind string
+
+const (
+	// Added 表示内存只存在于源端
+	Added ChangeKind = "added"
+	// Changed 表示两端都存在但文本或 metadata 不同
+	Changed ChangeKind = "changed"
+	// Removed 表示内存只存在于目标
+	Removed ChangeKind = "removed"
+)
+
+// Change 是一条差异
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Key 是匹配两端内存的键
+	Key string `json:"key"`
+	// Source 和 Target 是两端的内存, Added 时 Target 为 nil, Removed 时 Source 为 nil
+	Source *types.Memory `json:"source,omitempty"`
+	Target *types.Memory `json:"target,omitempty"`
+	// TextChanged 和 MetadataChanged 说明 Changed 的内容
+	TextChanged     bool `json:"text_changed,omitempty"`
+	MetadataChanged bool `json:"metadata_changed,omitempty"`
+	// Skipped 表示冲突策略保留了目标的内容, Apply 不会应用这条差异
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// Plan 是 Diff 的结果, 可以序列化为 JSON 保存, 之后再 Apply
+type Plan struct {
+	Source  Scope    `json:"source"`
+	Target  Scope    `json:"target"`
+	Options Options  `json:"options"`
+	Changes []Change `json:"changes"`
+}
+
+// Count 返回某种差异的数量, 不包括 Skipped 的差异
+func (p *Plan) Count(kind ChangeKind) int {
+	n := 0
+	for _, change := range p.Changes {
+		if change.Kind == kind && !change.Skipped {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty 判断是否没有需要应用的差异
+func (p *Plan) Empty() bool {
+	for _, change := range p.Changes {
+		if !change.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff 读取两端范围内的所有内存并比较
+// 两端都没有限定实体时 (例如同步整个项目), 实体 ID 也是匹配键的一部分
+// 同一端有多个内存的键相同时, 只比较第一个
+func Diff(ctx context.Context, source, target Side, opts Options) (*Plan, error) {
+	withEntity := !source.Scope.hasEntity() && !target.Scope.hasEntity()
+
+	sourceMemories, sourceOrder, err := load(ctx, source, opts, withEntity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list source memories")
+	}
+	targetMemories, targetOrder, err := load(ctx, target, opts, withEntity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list target memories")
+	}
+
+	plan := &Plan{Source: source.Scope, Target: target.Scope, Options: opts}
+	for _, key := range sourceOrder {
+		src := sourceMemories[key]
+		dst, ok := targetMemories[key]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Kind: Added, Key: key, Source: src})
+			continue
+		}
+
+		change := Change{Kind: Changed, Key: key, Source: src, Target: dst}
+		change.TextChanged = src.Memory != dst.Memory
+		change.MetadataChanged = !metadataEqual(src.Metadata, dst.Metadata, opts.IgnoreMetadata)
+		if !change.TextChanged && !change.MetadataChanged {
+			continue
+		}
+		switch opts.Conflict {
+		case TargetWins:
+			change.Skipped = true
+		case NewerWins:
+			change.Skipped = !updatedAt(src).After(updatedAt(dst))
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	for _, key := range targetOrder {
+		if _, ok := sourceMemories[key]; !ok {
+			plan.Changes = append(plan.Changes, Change{Kind: Removed, Key: key, Target: targetMemories[key]})
+		}
+	}
+	return plan, nil
+}
+
+// load 遍历一端的内存, 返回按键索引的内存和键的顺序
+func load(ctx context.Context, side Side, opts Options, withEntity bool) (map[string]*types.Memory, []string, error) {
+	memories := make(map[string]*types.Memory)
+	var order []string
+
+	it := side.Client.IterMemories(ctx, side.Scope.listOptions())
+	defer it.Close()
+	for it.Next() {
+		memory := it.Value()
+		key := matchKey(&memory, opts.MatchKey, withEntity)
+		if _, ok := memories[key]; ok {
+			continue
+		}
+		memories[key] = &memory
+		order = append(order, key)
+	}
+	return memories, order, it.Err()
+}
+
+// matchKey 返回内存的匹配键
+func matchKey(memory *types.Memory, metadataKey string, withEntity bool) string {
+	var key string
+	if value, ok := memory.Metadata[metadataKey]; ok && metadataKey != "" {
+		key = metadataKey + "=" + fmt.Sprint(value)
+	} else {
+		sum := md5.Sum([]byte(strings.TrimSpace(memory.Memory)))
+		key = "text:" + hex.EncodeToString(sum[:])
+	}
+	if withEntity {
+		key = strings.Join([]string{memory.UserID, memory.AgentID, memory.AppID, memory.RunID, key}, "/")
+	}
+	return key
+}
+
+// metadataEqual 比较两个 metadata, 忽略 ignore 中的键, nil 与空 map 相等
+func metadataEqual(a, b map[string]any, ignore []string) bool {
+	return reflect.DeepEqual(normalizeMetadata(a, ignore), normalizeMetadata(b, ignore))
+}
+
+// normalizeMetadata 通过 JSON 统一数字等类型, 使来自不同响应的 metadata 可以比较
+func normalizeMetadata(metadata map[string]any, ignore []string) map[string]any {
+	normalized := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		normalized[k] = v
+	}
+	for _, key := range ignore {
+		delete(normalized, key)
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return normalized
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return normalized
+	}
+	return out
+}
+
+// updatedAt 返回内存的更新时间, 没有时使用创建时间
+func updatedAt(memory *types.Memory) time.Time {
+	if !memory.UpdatedAt.IsZero() {
+		return memory.UpdatedAt
+	}
+	return memory.CreatedAt
+}
@@ -0,0 +1,298 @@
+package memsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+// store 是一个只实现同步所需接口的内存服务端
+type store struct {
+	mu       sync.Mutex
+	memories []types.Memory
+	nextID   int
+	// failBatch 使批量更新和删除返回 500
+	failBatch bool
+}
+
+func newStore(t *testing.T, memories ...types.Memory) (*store, *client.MemoryClient) {
+	s := &store{memories: memories, nextID: len(memories)}
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+
+	c, err := client.NewMemoryClient(client.ClientOptions{APIKey: "test-key", Host: server.URL, PingMode: client.PingNever})
+	require.NoError(t, err)
+	return s, c
+}
+
+func (s *store) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.failBatch && r.URL.Path == "/v1/memories/batch/":
+		http.Error(w, "batch unavailable", http.StatusInternalServerError)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/memories/":
+		var req struct {
+			Page     int            `json:"page"`
+			PageSize int            `json:"page_size"`
+			Filters  map[string]any `json:"filters"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var matched []types.Memory
+		for _, m := range s.memories {
+			if user, ok := req.Filters["user_id"].(string); ok && user != "*" && user != m.UserID {
+				continue
+			}
+			matched = append(matched, m)
+		}
+		page := []types.Memory{}
+		for i := (req.Page - 1) * req.PageSize; i < req.Page*req.PageSize && i < len(matched); i++ {
+			page = append(page, matched[i])
+		}
+		json.NewEncoder(w).Encode(page)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/memories/":
+		var req struct {
+			Messages []types.Message `json:"messages"`
+			UserID   string          `json:"user_id"`
+			Metadata map[string]any  `json:"metadata"`
+			Infer    *bool           `json:"infer"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Infer == nil || *req.Infer {
+			http.Error(w, "expected infer=false", http.StatusBadRequest)
+			return
+		}
+		s.nextID++
+		m := types.Memory{ID: fmt.Sprintf("new-%d", s.nextID), Memory: req.Messages[0].Content, UserID: req.UserID, Metadata: req.Metadata}
+		s.memories = append(s.memories, m)
+		json.NewEncoder(w).Encode([]types.Memory{m})
+
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/memories/batch/":
+		var bodies []types.MemoryUpdateBody
+		json.NewDecoder(r.Body).Decode(&bodies)
+		for _, body := range bodies {
+			for i := range s.memories {
+				if s.memories[i].ID == body.MemoryID {
+					s.memories[i].Memory = body.Text
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodDelete && r.URL.Path == "/v1/memories/batch/":
+		var ids []string
+		json.NewDecoder(r.Body).Decode(&ids)
+		deleted := make(map[string]bool)
+		for _, id := range ids {
+			deleted[id] = true
+		}
+		kept := s.memories[:0]
+		for _, m := range s.memories {
+			if !deleted[m.ID] {
+				kept = append(kept, m)
+			}
+		}
+		s.memories = kept
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "unexpected request", http.StatusNotFound)
+	}
+}
+
+// texts 返回排序后的 "user: text metadata" 列表
+func (s *store) texts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var texts []string
+	for _, m := range s.memories {
+		texts = append(texts, fmt.Sprintf("%s: %s %v", m.UserID, m.Memory, m.Metadata))
+	}
+	sort.Strings(texts)
+	return texts
+}
+
+func changeSummary(plan *Plan) []string {
+	var out []string
+	for _, change := range plan.Changes {
+		s := string(change.Kind) + " " + change.Key
+		if change.Skipped {
+			s += " (skipped)"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestDiffAndApplyByMatchKey(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	_, source := newStore(t,
+		types.Memory{ID: "s1", UserID: "alice", Memory: "likes tea", Metadata: map[string]any{"sid": "1"}},
+		types.Memory{ID: "s2", UserID: "alice", Memory: "lives in Paris", Metadata: map[string]any{"sid": "2"}, UpdatedAt: newer},
+		types.Memory{ID: "s3", UserID: "alice", Memory: "has a cat", Metadata: map[string]any{"sid": "3", "team": "core", "synced_at": 1}},
+		types.Memory{ID: "s4", UserID: "alice", Memory: "same", Metadata: map[string]any{"sid": "4", "synced_at": 1}},
+		types.Memory{ID: "s5", UserID: "bob", Memory: "other user"},
+	)
+	target, targetClient := newStore(t,
+		types.Memory{ID: "t2", UserID: "alice", Memory: "lives in Lyon", Metadata: map[string]any{"sid": "2"}, UpdatedAt: older},
+		types.Memory{ID: "t3", UserID: "alice", Memory: "has a cat", Metadata: map[string]any{"sid": "3", "team": "infra"}},
+		types.Memory{ID: "t4", UserID: "alice", Memory: "same", Metadata: map[string]any{"sid": "4", "synced_at": 2}},
+		types.Memory{ID: "t9", UserID: "alice", Memory: "stale", Metadata: map[string]any{"sid": "9"}},
+	)
+
+	scope := Scope{UserID: "alice"}
+	opts := Options{MatchKey: "sid", IgnoreMetadata: []string{"synced_at"}}
+	ctx := context.Background()
+
+	plan, err := Diff(ctx, Side{Client: source, Scope: scope}, Side{Client: targetClient, Scope: scope}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"added sid=1", "changed sid=2", "changed sid=3", "removed sid=9"}, changeSummary(plan))
+	assert.True(t, plan.Changes[1].TextChanged)
+	assert.False(t, plan.Changes[1].MetadataChanged)
+	assert.True(t, plan.Changes[2].MetadataChanged)
+	assert.Equal(t, 1, plan.Count(Added))
+	assert.Equal(t, 2, plan.Count(Changed))
+	assert.Equal(t, 1, plan.Count(Removed))
+
+	// 计划可以保存为 JSON 后再应用
+	data, err := json.Marshal(plan)
+	require.NoError(t, err)
+	var loaded Plan
+	require.NoError(t, json.Unmarshal(data, &loaded))
+
+	report, err := Apply(ctx, targetClient, &loaded, ApplyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Applied(Added))
+	assert.Equal(t, 2, report.Applied(Changed))
+	assert.Equal(t, 0, report.Applied(Removed))
+	assert.Equal(t, []string{
+		"alice: has a cat map[sid:3 synced_at:1 team:core]",
+		"alice: likes tea map[sid:1]",
+		"alice: lives in Paris map[sid:2]",
+		"alice: same map[sid:4 synced_at:2]",
+		"alice: stale map[sid:9]",
+	}, target.texts())
+
+	plan, err = Diff(ctx, Side{Client: source, Scope: scope}, Side{Client: targetClient, Scope: scope}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"removed sid=9"}, changeSummary(plan))
+	report, err = Apply(ctx, targetClient, plan, ApplyOptions{Prune: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Applied(Removed))
+	assert.Len(t, target.texts(), 4)
+	assert.NotContains(t, target.texts(), "alice: stale map[sid:9]")
+
+	plan, err = Diff(ctx, Side{Client: source, Scope: scope}, Side{Client: targetClient, Scope: scope}, opts)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), "%v", changeSummary(plan))
+}
+
+func TestDiffConflictPolicies(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	_, source := newStore(t,
+		types.Memory{ID: "s1", UserID: "alice", Memory: "v2", Metadata: map[string]any{"sid": "1"}, UpdatedAt: newer},
+		types.Memory{ID: "s2", UserID: "alice", Memory: "v1", Metadata: map[string]any{"sid": "2"}, UpdatedAt: older},
+	)
+	_, target := newStore(t,
+		types.Memory{ID: "t1", UserID: "alice", Memory: "v1", Metadata: map[string]any{"sid": "1"}, UpdatedAt: older},
+		types.Memory{ID: "t2", UserID: "alice", Memory: "v2", Metadata: map[string]any{"sid": "2"}, UpdatedAt: newer},
+	)
+
+	for _, tt := range []struct {
+		policy ConflictPolicy
+		want   []string
+	}{
+		{SourceWins, []string{"changed sid=1", "changed sid=2"}},
+		{TargetWins, []string{"changed sid=1 (skipped)", "changed sid=2 (skipped)"}},
+		{NewerWins, []string{"changed sid=1", "changed sid=2 (skipped)"}},
+	} {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			plan, err := Diff(context.Background(),
+				Side{Client: source, Scope: Scope{UserID: "alice"}},
+				Side{Client: target, Scope: Scope{UserID: "alice"}},
+				Options{MatchKey: "sid", Conflict: tt.policy})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changeSummary(plan))
+		})
+	}
+
+	policy, err := ParseConflictPolicy("newer")
+	require.NoError(t, err)
+	assert.Equal(t, NewerWins, policy)
+	_, err = ParseConflictPolicy("latest")
+	assert.Error(t, err)
+}
+
+func TestSyncWholeProjectByText(t *testing.T) {
+	_, source := newStore(t,
+		types.Memory{ID: "s1", UserID: "alice", Memory: "likes tea"},
+		types.Memory{ID: "s2", UserID: "bob", Memory: "likes tea"},
+	)
+	target, targetClient := newStore(t,
+		types.Memory{ID: "t1", UserID: "alice", Memory: "likes tea"},
+	)
+
+	plan, err := Diff(context.Background(), Side{Client: source}, Side{Client: targetClient}, Options{})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, Added, plan.Changes[0].Kind)
+	assert.Equal(t, "bob", plan.Changes[0].Source.UserID)
+
+	_, err = Apply(context.Background(), targetClient, plan, ApplyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice: likes tea map[]", "bob: likes tea map[]"}, target.texts())
+}
+
+func TestApplyReportsUnconfirmedChanges(t *testing.T) {
+	_, source := newStore(t,
+		types.Memory{ID: "s1", UserID: "alice", Memory: "likes tea", Metadata: map[string]any{"sid": "1"}},
+		types.Memory{ID: "s2", UserID: "alice", Memory: "lives in Paris", Metadata: map[string]any{"sid": "2"}},
+		types.Memory{ID: "s3", UserID: "alice", Memory: "has a cat", Metadata: map[string]any{"sid": "3", "team": "core"}},
+	)
+	target, targetClient := newStore(t,
+		types.Memory{ID: "t2", UserID: "alice", Memory: "lives in Lyon", Metadata: map[string]any{"sid": "2"}},
+		types.Memory{ID: "t3", UserID: "alice", Memory: "has a cat", Metadata: map[string]any{"sid": "3", "team": "infra"}},
+		types.Memory{ID: "t9", UserID: "alice", Memory: "stale", Metadata: map[string]any{"sid": "9"}},
+	)
+
+	scope := Scope{UserID: "alice"}
+	plan, err := Diff(context.Background(), Side{Client: source, Scope: scope}, Side{Client: targetClient, Scope: scope}, Options{MatchKey: "sid"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"added sid=1", "changed sid=2", "changed sid=3", "removed sid=9"}, changeSummary(plan))
+
+	// 取消的 ctx 不会应用任何差异
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Apply(ctx, targetClient, plan, ApplyOptions{Prune: true})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, report.Failed(), 4)
+
+	// 批量请求失败时, 更新, 删除和需要删除旧内存的替换都没有应用
+	target.failBatch = true
+	report, err = Apply(context.Background(), targetClient, plan, ApplyOptions{Prune: true})
+	assert.Error(t, err)
+	assert.Equal(t, 1, report.Applied(Added))
+	assert.Equal(t, 0, report.Applied(Changed))
+	assert.Equal(t, 0, report.Applied(Removed))
+	assert.Len(t, report.Failed(), 3)
+}
@@ -0,0 +1,215 @@
+// Package otelmem0 为 mem0 客户端提供 OpenTelemetry 追踪和指标
+//
+// 它以 client.Interceptor 的形式接入, 核心的 client 包不依赖 OpenTelemetry:
+//
+//	interceptor, err := otelmem0.NewInterceptor()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	mem0, err := client.NewMemoryClient(options, client.WithInterceptors(interceptor))
+package otelmem0
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bytectlgo/mem0-go/client"
+)
+
+// instrumentationName 是 tracer 和 meter 的名称
+const instrumentationName = "github.com/bytectlgo/mem0-go/otelmem0"
+
+// 属性名称
+const (
+	AttrOperation   = attribute.Key("mem0.operation")
+	AttrEndpoint    = attribute.Key("mem0.endpoint")
+	AttrResultCount = attribute.Key("mem0.result_count")
+	AttrRetryCount  = attribute.Key("mem0.retry_count")
+	AttrMethod      = attribute.Key("http.request.method")
+	AttrStatusCode  = attribute.Key("http.response.status_code")
+	AttrErrorType   = attribute.Key("error.type")
+)
+
+// 指标名称
+const (
+	MetricDuration = "mem0.client.request.duration"
+	MetricErrors   = "mem0.client.request.errors"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option 定义拦截器的可选配置
+type Option func(*config)
+
+// WithTracerProvider 设置 TracerProvider, 默认使用 otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider 设置 MeterProvider, 默认使用 otel.GetMeterProvider()
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagator 设置用于向请求头注入追踪上下文的 propagator, 默认使用 otel.GetTextMapPropagator()
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// NewInterceptor 创建为每次 API 调用生成 span 并记录耗时和错误数的拦截器
+//
+// span 名称为 "mem0.<Operation>", 例如 "mem0.Search", 并携带操作名, 接口路径,
+// 状态码, 返回结果数量和重试次数等属性. 指标只使用低基数的属性 (操作名, HTTP 方法和状态码).
+func NewInterceptor(opts ...Option) (client.Interceptor, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+	meter := cfg.meterProvider.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram(MetricDuration,
+		metric.WithDescription("Duration of mem0 API calls, including retries"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create duration histogram")
+	}
+
+	errorCount, err := meter.Int64Counter(MetricErrors,
+		metric.WithDescription("Number of failed mem0 API calls"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create error counter")
+	}
+
+	return func(ctx context.Context, req *client.Request, next client.Handler) (*client.Response, error) {
+		ctx, span := tracer.Start(ctx, "mem0."+req.Operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				AttrOperation.String(req.Operation),
+				AttrMethod.String(req.Method),
+				AttrEndpoint.String(req.Path),
+			),
+		)
+		defer span.End()
+
+		cfg.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		start := time.Now()
+		resp, err := next(ctx, req)
+		elapsed := time.Since(start)
+
+		metricAttrs := []attribute.KeyValue{
+			AttrOperation.String(req.Operation),
+			AttrMethod.String(req.Method),
+		}
+
+		if resp != nil {
+			if resp.StatusCode > 0 {
+				span.SetAttributes(AttrStatusCode.Int(resp.StatusCode))
+				metricAttrs = append(metricAttrs, AttrStatusCode.Int(resp.StatusCode))
+			}
+			if resp.Attempts > 1 {
+				span.SetAttributes(AttrRetryCount.Int(resp.Attempts - 1))
+			} else {
+				span.SetAttributes(AttrRetryCount.Int(0))
+			}
+			if count, ok := resultCount(resp.Result); ok {
+				span.SetAttributes(AttrResultCount.Int(count))
+			}
+		}
+
+		if err != nil {
+			errorType := errorType(err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(AttrErrorType.String(errorType))
+			metricAttrs = append(metricAttrs, AttrErrorType.String(errorType))
+			errorCount.Add(ctx, 1, metric.WithAttributes(metricAttrs...))
+		}
+
+		duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(metricAttrs...))
+
+		return resp, err
+	}, nil
+}
+
+// resultCount 返回解码后结果中的条目数: 切片返回其长度, 其他非空值返回 1
+func resultCount(result interface{}) (int, bool) {
+	if result == nil {
+		return 0, false
+	}
+
+	v := reflect.ValueOf(result)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Len(), true
+	case reflect.Struct:
+		// 分页响应, 例如 GetEventsResponse 和 AllUsers
+		if results := v.FieldByName("Results"); results.IsValid() && results.Kind() == reflect.Slice {
+			return results.Len(), true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// errorType 返回低基数的错误分类
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, client.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, client.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, client.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, client.ErrValidation):
+		return "validation"
+	case errors.Is(err, client.ErrServer):
+		return "server"
+	}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode > 0 {
+		return http.StatusText(apiErr.StatusCode)
+	}
+	return "other"
+}
@@ -0,0 +1,111 @@
+package otelmem0
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/bytectlgo/mem0-go/client"
+	"github.com/bytectlgo/mem0-go/types"
+)
+
+func TestInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("Traceparent"))
+		switch r.URL.Path {
+		case "/v2/memories/search/":
+			json.NewEncoder(w).Encode([]types.Memory{{ID: "a"}, {ID: "b"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	interceptor, err := NewInterceptor(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	require.NoError(t, err)
+
+	mem0, err := client.NewMemoryClient(client.ClientOptions{
+		APIKey:   "test-key",
+		Host:     server.URL,
+		PingMode: client.PingNever,
+	}, client.WithInterceptors(interceptor))
+	require.NoError(t, err)
+
+	_, err = mem0.SearchContext(context.Background(), "test", nil)
+	require.NoError(t, err)
+	_, err = mem0.Get("missing")
+	require.ErrorIs(t, err, client.ErrNotFound)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+
+	search := ended[0]
+	assert.Equal(t, "mem0.Search", search.Name())
+	assert.Equal(t, codes.Unset, search.Status().Code)
+	assert.Contains(t, search.Attributes(), AttrOperation.String("Search"))
+	assert.Contains(t, search.Attributes(), AttrEndpoint.String("/v2/memories/search/"))
+	assert.Contains(t, search.Attributes(), AttrStatusCode.Int(http.StatusOK))
+	assert.Contains(t, search.Attributes(), AttrResultCount.Int(2))
+	assert.Contains(t, search.Attributes(), AttrRetryCount.Int(0))
+
+	get := ended[1]
+	assert.Equal(t, "mem0.Get", get.Name())
+	assert.Equal(t, codes.Error, get.Status().Code)
+	assert.Contains(t, get.Attributes(), AttrStatusCode.Int(http.StatusNotFound))
+	assert.Contains(t, get.Attributes(), AttrErrorType.String("not_found"))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	duration := metrics[MetricDuration].Data.(metricdata.Histogram[float64])
+	assert.Len(t, duration.DataPoints, 2)
+
+	errorCount := metrics[MetricErrors].Data.(metricdata.Sum[int64])
+	require.Len(t, errorCount.DataPoints, 1)
+	assert.Equal(t, int64(1), errorCount.DataPoints[0].Value)
+	operation, _ := errorCount.DataPoints[0].Attributes.Value(AttrOperation)
+	assert.Equal(t, attribute.StringValue("Get"), operation)
+}
+
+func TestResultCount(t *testing.T) {
+	memories := []types.Memory{{}, {}, {}}
+	count, ok := resultCount(&memories)
+	assert.True(t, ok)
+	assert.Equal(t, 3, count)
+
+	count, ok = resultCount(&types.Memory{})
+	assert.True(t, ok)
+	assert.Equal(t, 1, count)
+
+	count, ok = resultCount(&types.GetEventsResponse{Results: []types.Event{{}, {}}})
+	assert.True(t, ok)
+	assert.Equal(t, 2, count)
+
+	_, ok = resultCount(nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AddEventPayload 是 ADD 事件的请求内容
+type AddEventPayload struct {
+	Messages  []Message      `json:"messages,omitempty"`
+	UserID    string         `json:"user_id,omitempty"`
+	AgentID   string         `json:"agent_id,omitempty"`
+	AppID     string         `json:"app_id,omitempty"`
+	RunID     string         `json:"run_id,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Infer     *bool          `json:"infer,omitempty"`
+	Version   APIVersion     `json:"version,omitempty"`
+	OrgID     string         `json:"org_id,omitempty"`
+	ProjectID string         `json:"project_id,omitempty"`
+}
+
+// UpdateEventPayload 是 UPDATE 事件的请求内容
+type UpdateEventPayload struct {
+	MemoryID string         `json:"memory_id,omitempty"`
+	Text     string         `json:"text,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// DeleteEventPayload 是 DELETE 事件的请求内容
+type DeleteEventPayload struct {
+	MemoryID  string   `json:"memory_id,omitempty"`
+	MemoryIDs []string `json:"memory_ids,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	AgentID   string   `json:"agent_id,omitempty"`
+	AppID     string   `json:"app_id,omitempty"`
+	RunID     string   `json:"run_id,omitempty"`
+}
+
+// SearchEventPayload 是 SEARCH 事件的请求内容
+type SearchEventPayload struct {
+	Query     string         `json:"query,omitempty"`
+	Filters   map[string]any `json:"filters,omitempty"`
+	TopK      int            `json:"top_k,omitempty"`
+	Threshold float64        `json:"threshold,omitempty"`
+	Version   APIVersion     `json:"version,omitempty"`
+}
+
+// GetAllEventPayload 是 GET_ALL 事件的请求内容
+type GetAllEventPayload struct {
+	Filters  map[string]any `json:"filters,omitempty"`
+	Page     int            `json:"page,omitempty"`
+	PageSize int            `json:"page_size,omitempty"`
+	Version  APIVersion     `json:"version,omitempty"`
+}
+
+// DecodePayload 将事件的 Payload 解码到 v 中
+func (e *Event) DecodePayload(v any) error {
+	return remarshal(e.Payload, v)
+}
+
+// DecodeResults 将事件的 Results 解码到 v 中, v 通常是指向切片的指针
+func (e *Event) DecodeResults(v any) error {
+	return remarshal(e.Results, v)
+}
+
+// MemoryResults 返回事件影响的内存, 每条内存的 Event 字段表示它被添加, 更新还是删除
+// SEARCH 和 GET_ALL 事件返回查询到的内存
+func (e *Event) MemoryResults() ([]Memory, error) {
+	if len(e.Results) == 0 {
+		return nil, nil
+	}
+
+	var memories []Memory
+	if err := e.DecodeResults(&memories); err != nil {
+		return nil, err
+	}
+	return memories, nil
+}
+
+// TypedPayload 根据 EventType 将 Payload 解码为对应的类型:
+// *AddEventPayload, *UpdateEventPayload, *DeleteEventPayload, *SearchEventPayload 或 *GetAllEventPayload
+func (e *Event) TypedPayload() (any, error) {
+	var payload any
+	switch e.EventType {
+	case EventTypeMemoryAdd:
+		payload = &AddEventPayload{}
+	case EventTypeMemoryUpdate:
+		payload = &UpdateEventPayload{}
+	case EventTypeMemoryDelete:
+		payload = &DeleteEventPayload{}
+	case EventTypeSearch:
+		payload = &SearchEventPayload{}
+	case EventTypeGetAll:
+		payload = &GetAllEventPayload{}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", e.EventType)
+	}
+
+	if err := e.DecodePayload(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// AddPayload 返回 ADD 事件的请求内容
+func (e *Event) AddPayload() (*AddEventPayload, error) {
+	return decodePayloadOf[AddEventPayload](e, EventTypeMemoryAdd)
+}
+
+// UpdatePayload 返回 UPDATE 事件的请求内容
+func (e *Event) UpdatePayload() (*UpdateEventPayload, error) {
+	return decodePayloadOf[UpdateEventPayload](e, EventTypeMemoryUpdate)
+}
+
+// DeletePayload 返回 DELETE 事件的请求内容
+func (e *Event) DeletePayload() (*DeleteEventPayload, error) {
+	return decodePayloadOf[DeleteEventPayload](e, EventTypeMemoryDelete)
+}
+
+// SearchPayload 返回 SEARCH 事件的请求内容
+func (e *Event) SearchPayload() (*SearchEventPayload, error) {
+	return decodePayloadOf[SearchEventPayload](e, EventTypeSearch)
+}
+
+// GetAllPayload 返回 GET_ALL 事件的请求内容
+func (e *Event) GetAllPayload() (*GetAllEventPayload, error) {
+	return decodePayloadOf[GetAllEventPayload](e, EventTypeGetAll)
+}
+
+// decodePayloadOf 在事件类型匹配时将 Payload 解码为 T
+func decodePayloadOf[T any](e *Event, eventType EventType) (*T, error) {
+	if e.EventType != eventType {
+		return nil, fmt.Errorf("event %s has type %q, not %q", e.ID, e.EventType, eventType)
+	}
+
+	var payload T
+	if err := e.DecodePayload(&payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// remarshal 通过 JSON 将 src 转换为 dst
+func remarshal(src, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to unmarshal event data: %w", err)
+	}
+	return nil
+}
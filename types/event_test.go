@@ -0,0 +1,69 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const addEventJSON = `{
+	"id": "ev-1",
+	"event_type": "ADD",
+	"status": "SUCCEEDED",
+	"payload": {
+		"messages": [{"role": "user", "content": "I like pizza"}],
+		"user_id": "alice",
+		"metadata": {"source": "chat"}
+	},
+	"results": [
+		{"id": "mem-1", "event": "ADD", "data": {"memory": "Likes pizza"}},
+		{"id": "mem-2", "event": "DELETE", "data": {"memory": "Dislikes pizza"}}
+	]
+}`
+
+func TestEventMemoryResults(t *testing.T) {
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(addEventJSON), &event))
+
+	memories, err := event.MemoryResults()
+	require.NoError(t, err)
+	require.Len(t, memories, 2)
+	assert.Equal(t, "mem-1", memories[0].ID)
+	assert.Equal(t, EventTypeMemoryAdd, memories[0].Event)
+	assert.Equal(t, "Likes pizza", memories[0].Data.Memory)
+	assert.Equal(t, EventTypeMemoryDelete, memories[1].Event)
+
+	memories, err = (&Event{}).MemoryResults()
+	assert.NoError(t, err)
+	assert.Nil(t, memories)
+}
+
+func TestEventTypedPayload(t *testing.T) {
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(addEventJSON), &event))
+
+	payload, err := event.TypedPayload()
+	require.NoError(t, err)
+	add, ok := payload.(*AddEventPayload)
+	require.True(t, ok)
+	assert.Equal(t, "alice", add.UserID)
+	assert.Equal(t, []Message{{Role: "user", Content: "I like pizza"}}, add.Messages)
+	assert.Equal(t, map[string]any{"source": "chat"}, add.Metadata)
+
+	add, err = event.AddPayload()
+	require.NoError(t, err)
+	assert.Equal(t, "alice", add.UserID)
+
+	_, err = event.SearchPayload()
+	assert.Error(t, err)
+
+	search := Event{EventType: EventTypeSearch, Payload: map[string]any{"query": "food", "top_k": 3}}
+	payload, err = search.TypedPayload()
+	require.NoError(t, err)
+	assert.Equal(t, &SearchEventPayload{Query: "food", TopK: 3}, payload)
+
+	_, err = (&Event{EventType: "UNKNOWN"}).TypedPayload()
+	assert.Error(t, err)
+}
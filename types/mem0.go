@@ -32,8 +32,10 @@ const (
 	VeryNegative Feedback = "VERY_NEGATIVE"
 )
 
-// MemoryOptions
-// TODO: intx4 -- rework these options to distinguish between Add and Search options
+// MemoryOptions 是 Add, Search 和 GetAll 曾经共用的选项, 其中许多字段只对部分操作有效
+//
+// Deprecated: 使用 AddOptions, QueryOptions, ListOptions 或 DeleteAllOptions,
+// 旧代码可以通过 ToAddOptions 等方法转换.
 type MemoryOptions struct {
 	APIVersion APIVersion     `json:"api_version,omitempty"`
 	Version    APIVersion     `json:"version,omitempty"`
@@ -200,6 +202,14 @@ type AllUsers struct {
 	Previous any    `json:"previous"`
 }
 
+// PingResponse 定义 ping 响应
+type PingResponse struct {
+	Status    string `json:"status"`
+	OrgID     string `json:"org_id"`
+	ProjectID string `json:"project_id"`
+	UserEmail string `json:"user_email"`
+}
+
 // ProjectResponse 定义项目响应
 type ProjectResponse struct {
 	CustomInstructions string   `json:"custom_instructions,omitempty"`
@@ -236,6 +246,8 @@ type Webhook struct {
 
 // WebhookPayload is the payload for creating a webhook
 type WebhookPayload struct {
+	// WebhookID 只用于 UpdateWebhook, 指定要更新的 webhook
+	WebhookID  string         `json:"webhook_id,omitempty"`
 	EventTypes []WebhookEvent `json:"event_types"`
 	Name       string         `json:"name"`
 	URL        string         `json:"url"`
@@ -251,6 +263,9 @@ type FeedbackPayload struct {
 const SearchWildcard = "*"
 
 // SearchOptions 定义搜索选项
+//
+// Deprecated: SearchContext 使用 QueryOptions, GetAllContext 使用 ListOptions,
+// 旧代码可以通过 ToQueryOptions 和 ToListOptions 转换.
 type SearchOptions struct {
 	MemoryOptions
 	EnableGraph             bool       `json:"enable_graph,omitempty"`
@@ -300,7 +315,7 @@ func structToQuery(v interface{}) string {
 		}
 
 		// 获取 JSON 标签
-		tag := field.Tag.Get("json")
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
 		if tag == "" || tag == "-" {
 			continue
 		}
@@ -0,0 +1,209 @@
+package types
+
+// AddOptions 定义 AddContext 和 AddAsyncContext 的选项
+type AddOptions struct {
+	Version APIVersion `json:"version,omitempty"`
+
+	// 内存所属的实体, 至少需要指定一个
+	UserID  string `json:"user_id,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+	AppID   string `json:"app_id,omitempty"`
+	RunID   string `json:"run_id,omitempty"`
+
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// Timestamp 是内存的 Unix 时间戳 (秒), 为 0 时使用服务端当前时间
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Infer 为 nil 时使用服务端默认值 (true), 设置为 false 时原样保存消息
+	Infer              *bool            `json:"infer,omitempty"`
+	Includes           string           `json:"includes,omitempty"`
+	Excludes           string           `json:"excludes,omitempty"`
+	EnableGraph        bool             `json:"enable_graph,omitempty"`
+	CustomCategories   CustomCategories `json:"custom_categories,omitempty"`
+	CustomInstructions string           `json:"custom_instructions,omitempty"`
+
+	// OrgID 和 ProjectID 为空时使用客户端的组织和项目
+	OrgID     string `json:"org_id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// QueryOptions 定义 SearchContext 的选项
+type QueryOptions struct {
+	Version APIVersion `json:"version,omitempty"`
+
+	// Filters 是 v2 过滤条件, 可以使用 filters 包构建
+	// NOTE: you MUST not specify both agent_id and user_id as they pertain to different isolated scopes
+	// See https://docs.mem0.ai/platform/features/v2-memory-filters#best-practices
+	Filters map[string]any `json:"filters,omitempty"`
+
+	// 实体 ID 只用于 V1 搜索, V2 搜索请使用 Filters
+	UserID  string `json:"user_id,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+	AppID   string `json:"app_id,omitempty"`
+	RunID   string `json:"run_id,omitempty"`
+
+	TopK                    int      `json:"top_k,omitempty"`
+	Threshold               float64  `json:"threshold,omitempty"`
+	Fields                  []string `json:"fields,omitempty"`
+	Categories              []string `json:"categories,omitempty"`
+	Rerank                  bool     `json:"rerank,omitempty"`
+	KeywordSearch           bool     `json:"keyword_search,omitempty"`
+	OnlyMetadataBasedSearch bool     `json:"only_metadata_based_search,omitempty"`
+	EnableGraph             bool     `json:"enable_graph,omitempty"`
+
+	// OrgID 和 ProjectID 为空时使用客户端的组织和项目
+	OrgID     string `json:"org_id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// ListOptions 定义 GetAllContext 和 IterMemories 的选项
+type ListOptions struct {
+	Version APIVersion `json:"version,omitempty"`
+
+	// Filters 是 v2 过滤条件, 可以使用 filters 包构建
+	// NOTE: you MUST not specify both agent_id and user_id as they pertain to different isolated scopes
+	// See https://docs.mem0.ai/platform/features/v2-memory-filters#best-practices
+	Filters map[string]any `json:"filters,omitempty"`
+	// Categories 只返回属于这些分类的内存, 不能同时在 Filters 中指定 categories
+	Categories []string `json:"categories,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+
+	Page     int `json:"page,omitempty"`
+	PageSize int `json:"page_size,omitempty"`
+
+	// OrgID 和 ProjectID 为空时使用客户端的组织和项目
+	OrgID     string `json:"org_id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// DeleteAllOptions 定义 DeleteAllContext 的选项, 只删除属于指定实体的内存
+type DeleteAllOptions struct {
+	UserID  string `json:"user_id,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+	AppID   string `json:"app_id,omitempty"`
+	RunID   string `json:"run_id,omitempty"`
+
+	OrgID     string `json:"org_id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// ToQuery 将结构体转换为 URL 查询字符串
+func (o DeleteAllOptions) ToQuery() string {
+	return structToQuery(o)
+}
+
+// version 返回设置的 API 版本, Version 为空时使用 APIVersion
+func (o MemoryOptions) version() APIVersion {
+	if o.Version != "" {
+		return o.Version
+	}
+	return o.APIVersion
+}
+
+// ToAddOptions 将 MemoryOptions 转换为 AddOptions, 只保留 Add 支持的字段
+//
+// Deprecated: 直接使用 AddOptions.
+func (o MemoryOptions) ToAddOptions() AddOptions {
+	opts := AddOptions{
+		Version:            o.version(),
+		UserID:             o.UserID,
+		AgentID:            o.AgentID,
+		AppID:              o.AppID,
+		RunID:              o.RunID,
+		Metadata:           o.Metadata,
+		Timestamp:          o.Timestamp,
+		Includes:           o.Includes,
+		Excludes:           o.Excludes,
+		EnableGraph:        o.EnableGraph,
+		CustomCategories:   o.CustomCategories,
+		CustomInstructions: o.CustomInstructions,
+		OrgID:              o.OrgID,
+		ProjectID:          o.ProjectID,
+	}
+	// MemoryOptions 无法表示 infer=false, 只有 true 会被发送
+	if o.Infer {
+		infer := true
+		opts.Infer = &infer
+	}
+	return opts
+}
+
+// ToQueryOptions 将 MemoryOptions 转换为 QueryOptions, 只保留 Search 支持的字段
+//
+// Deprecated: 直接使用 QueryOptions.
+func (o MemoryOptions) ToQueryOptions() *QueryOptions {
+	return &QueryOptions{
+		Version:     o.version(),
+		Filters:     o.Filters,
+		UserID:      o.UserID,
+		AgentID:     o.AgentID,
+		AppID:       o.AppID,
+		RunID:       o.RunID,
+		EnableGraph: o.EnableGraph,
+		OrgID:       o.OrgID,
+		ProjectID:   o.ProjectID,
+	}
+}
+
+// ToListOptions 将 MemoryOptions 转换为 ListOptions, 只保留 GetAll 支持的字段
+//
+// Deprecated: 直接使用 ListOptions.
+func (o MemoryOptions) ToListOptions() *ListOptions {
+	return &ListOptions{
+		Version:   o.version(),
+		Filters:   o.Filters,
+		Page:      o.Page,
+		PageSize:  o.PageSize,
+		OrgID:     o.OrgID,
+		ProjectID: o.ProjectID,
+	}
+}
+
+// ToDeleteAllOptions 将 MemoryOptions 转换为 DeleteAllOptions, 只保留 DeleteAll 支持的字段
+//
+// Deprecated: 直接使用 DeleteAllOptions.
+func (o MemoryOptions) ToDeleteAllOptions() DeleteAllOptions {
+	return DeleteAllOptions{
+		UserID:    o.UserID,
+		AgentID:   o.AgentID,
+		AppID:     o.AppID,
+		RunID:     o.RunID,
+		OrgID:     o.OrgID,
+		ProjectID: o.ProjectID,
+	}
+}
+
+// version 返回设置的 API 版本, 外层的 Version 优先
+func (o SearchOptions) version() APIVersion {
+	if o.Version != "" {
+		return o.Version
+	}
+	return o.MemoryOptions.version()
+}
+
+// ToQueryOptions 将 SearchOptions 转换为 QueryOptions
+//
+// Deprecated: 直接使用 QueryOptions.
+func (o SearchOptions) ToQueryOptions() *QueryOptions {
+	opts := o.MemoryOptions.ToQueryOptions()
+	opts.Version = o.version()
+	opts.EnableGraph = o.EnableGraph || o.MemoryOptions.EnableGraph
+	opts.Threshold = o.Threshold
+	opts.TopK = o.TopK
+	opts.OnlyMetadataBasedSearch = o.OnlyMetadataBasedSearch
+	opts.KeywordSearch = o.KeywordSearch
+	opts.Fields = o.Fields
+	opts.Categories = o.Categories
+	opts.Rerank = o.Rerank
+	return opts
+}
+
+// ToListOptions 将 SearchOptions 转换为 ListOptions, 只保留 GetAll 支持的字段
+//
+// Deprecated: 直接使用 ListOptions.
+func (o SearchOptions) ToListOptions() *ListOptions {
+	opts := o.MemoryOptions.ToListOptions()
+	opts.Version = o.version()
+	opts.Fields = o.Fields
+	opts.Categories = o.Categories
+	return opts
+}
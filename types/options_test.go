@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOptionsInfer(t *testing.T) {
+	data, err := json.Marshal(AddOptions{UserID: "alice"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"user_id":"alice"}`, string(data))
+
+	infer := false
+	data, err = json.Marshal(AddOptions{UserID: "alice", Infer: &infer})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"user_id":"alice","infer":false}`, string(data))
+}
+
+func TestMemoryOptionsConversion(t *testing.T) {
+	old := MemoryOptions{
+		APIVersion: V1,
+		UserID:     "alice",
+		Infer:      true,
+		Filters:    map[string]any{"user_id": "alice"},
+		Page:       2,
+		PageSize:   10,
+		StartDate:  "2024-01-01",
+	}
+
+	add := old.ToAddOptions()
+	assert.Equal(t, V1, add.Version)
+	assert.Equal(t, "alice", add.UserID)
+	require.NotNil(t, add.Infer)
+	assert.True(t, *add.Infer)
+
+	search := old.ToQueryOptions()
+	assert.Equal(t, V1, search.Version)
+	assert.Equal(t, old.Filters, search.Filters)
+	assert.Equal(t, "alice", search.UserID)
+
+	list := old.ToListOptions()
+	assert.Equal(t, V1, list.Version)
+	assert.Equal(t, old.Filters, list.Filters)
+	assert.Equal(t, 2, list.Page)
+	assert.Equal(t, 10, list.PageSize)
+
+	assert.Equal(t, DeleteAllOptions{UserID: "alice"}, old.ToDeleteAllOptions())
+}
+
+func TestDeleteAllOptionsToQuery(t *testing.T) {
+	query, err := url.ParseQuery(DeleteAllOptions{UserID: "alice", RunID: "run-1"}.ToQuery())
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"user_id": {"alice"}, "run_id": {"run-1"}}, query)
+}
+
+func TestSearchOptionsConversion(t *testing.T) {
+	old := SearchOptions{
+		MemoryOptions: MemoryOptions{APIVersion: V1, Filters: map[string]any{"user_id": "alice"}, PageSize: 10},
+		TopK:          5,
+		Threshold:     0.3,
+		Categories:    []string{"food"},
+	}
+
+	search := old.ToQueryOptions()
+	assert.Equal(t, V1, search.Version)
+	assert.Equal(t, old.Filters, search.Filters)
+	assert.Equal(t, 5, search.TopK)
+	assert.Equal(t, 0.3, search.Threshold)
+	assert.Equal(t, []string{"food"}, search.Categories)
+
+	list := old.ToListOptions()
+	assert.Equal(t, V1, list.Version)
+	assert.Equal(t, 10, list.PageSize)
+	assert.Equal(t, []string{"food"}, list.Categories)
+
+	query, err := url.ParseQuery(old.ToQuery())
+	require.NoError(t, err)
+	assert.Equal(t, "5", query.Get("top_k"))
+}
@@ -0,0 +1,240 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrValidation 表示请求参数无效, 所有 ValidationError 都可以使用 errors.Is 与它比较
+var ErrValidation = errors.New("validation failed")
+
+// FieldError 是单个字段的校验错误
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError 列出选项中的所有问题
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		problems[i] = fe.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(problems, "; "))
+}
+
+// Is 使 errors.Is(err, ErrValidation) 返回 true
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Add 记录一个字段错误
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err 在没有问题时返回 nil, 否则返回 e
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate 检查 Add 的选项
+func (o AddOptions) Validate() error {
+	var errs ValidationError
+	validateVersion(&errs, o.Version)
+	if o.UserID == "" && o.AgentID == "" && o.AppID == "" && o.RunID == "" {
+		errs.Add("user_id", "at least one of user_id, agent_id, app_id or run_id is required")
+	}
+	if o.Timestamp < 0 {
+		errs.Add("timestamp", "must not be negative")
+	}
+	for i, category := range o.CustomCategories {
+		if category.CategoryName == "" {
+			errs.Add(fmt.Sprintf("custom_categories[%d]", i), "category name is required")
+		}
+	}
+	return errs.Err()
+}
+
+// Validate 检查 Search 的选项
+func (o QueryOptions) Validate() error {
+	var errs ValidationError
+	validateVersion(&errs, o.Version)
+	if o.Threshold < 0 || o.Threshold > 1 {
+		errs.Add("threshold", "must be between 0 and 1, got %g", o.Threshold)
+	}
+	if o.TopK < 0 {
+		errs.Add("top_k", "must not be negative, got %d", o.TopK)
+	}
+	if o.UserID != "" && o.AgentID != "" {
+		errs.Add("agent_id", "agent_id and user_id cannot be used together")
+	}
+	validateFilters(&errs, o.Filters)
+	return errs.Err()
+}
+
+// Validate 检查 GetAll 的选项
+func (o ListOptions) Validate() error {
+	var errs ValidationError
+	validateVersion(&errs, o.Version)
+	if o.Page < 0 {
+		errs.Add("page", "must not be negative, got %d", o.Page)
+	}
+	if o.PageSize < 0 {
+		errs.Add("page_size", "must not be negative, got %d", o.PageSize)
+	}
+	if _, ok := o.Filters["categories"]; ok && len(o.Categories) > 0 {
+		errs.Add("categories", "must be specified either in Categories or in Filters, not both")
+	}
+	validateFilters(&errs, o.Filters)
+	return errs.Err()
+}
+
+// Validate 检查 DeleteAll 的选项, 至少需要一个实体 ID 以避免误删所有内存
+func (o DeleteAllOptions) Validate() error {
+	var errs ValidationError
+	if o.UserID == "" && o.AgentID == "" && o.AppID == "" && o.RunID == "" {
+		errs.Add("user_id", "at least one of user_id, agent_id, app_id or run_id is required")
+	}
+	return errs.Err()
+}
+
+// Validate 检查反馈请求体
+func (p FeedbackPayload) Validate() error {
+	var errs ValidationError
+	if p.MemoryID == "" {
+		errs.Add("memory_id", "is required")
+	}
+	switch p.Feedback {
+	case "", Positive, Negative, VeryNegative:
+	default:
+		errs.Add("feedback", "unknown value %q, expected %s, %s or %s", p.Feedback, Positive, Negative, VeryNegative)
+	}
+	return errs.Err()
+}
+
+// Validate 检查 Webhook 请求体
+func (p WebhookPayload) Validate() error {
+	var errs ValidationError
+	if p.Name == "" {
+		errs.Add("name", "is required")
+	}
+	if p.URL == "" {
+		errs.Add("url", "is required")
+	} else if u, err := url.Parse(p.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.Add("url", "%q is not an absolute http or https URL", p.URL)
+	}
+	for i, event := range p.EventTypes {
+		switch event {
+		case MemoryAdded, MemoryUpdated, MemoryDeleted:
+		default:
+			errs.Add(fmt.Sprintf("event_types[%d]", i), "unknown event type %q", event)
+		}
+	}
+	return errs.Err()
+}
+
+// validateVersion 检查 API 版本
+func validateVersion(errs *ValidationError, v APIVersion) {
+	switch v {
+	case "", V1, V2:
+	default:
+		errs.Add("version", "unknown API version %q", v)
+	}
+}
+
+// validateFilters 检查过滤条件的作用域规则
+// See https://docs.mem0.ai/platform/features/v2-memory-filters#best-practices
+func validateFilters(errs *ValidationError, filters map[string]any) {
+	if scopeConflict(filters) {
+		errs.Add("filters", "agent_id and user_id belong to different scopes and cannot be combined with AND")
+	}
+}
+
+// scopeConflict 判断过滤条件的同一个 AND 中是否同时限定了 user_id 和 agent_id
+// OR 和 NOT 的每个分支单独检查
+func scopeConflict(filters map[string]any) bool {
+	scopes := ScopedFields(filters)
+	if scopes["user_id"] && scopes["agent_id"] {
+		return true
+	}
+
+	for key, value := range filters {
+		switch key {
+		case "AND", "OR", "NOT":
+			for _, child := range filterList(value) {
+				if scopeConflict(child) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ScopedFields 返回过滤条件顶层和嵌套的 AND 中限定了具体值的实体字段 (user_id 和 agent_id),
+// 不包括 OR 和 NOT 的分支. 通配符和 ne 比较不限定作用域
+func ScopedFields(filters map[string]any) map[string]bool {
+	scopes := make(map[string]bool)
+	collectScopes(filters, scopes)
+	return scopes
+}
+
+// collectScopes 将 filters 顶层和嵌套的 AND 中限定了具体值的实体字段记录到 scopes
+func collectScopes(filters map[string]any, scopes map[string]bool) {
+	for key, value := range filters {
+		switch key {
+		case "user_id", "agent_id":
+			if scopedValue(value) {
+				scopes[key] = true
+			}
+		case "AND":
+			for _, child := range filterList(value) {
+				collectScopes(child, scopes)
+			}
+		}
+	}
+}
+
+// scopedValue 判断实体字段的值是否限定了作用域
+func scopedValue(value any) bool {
+	if ops, ok := value.(map[string]any); ok {
+		for op := range ops {
+			if op != "ne" {
+				return true
+			}
+		}
+		return false
+	}
+	return value != SearchWildcard
+}
+
+// filterList 将 AND, OR 和 NOT 的值转换为过滤条件列表
+func filterList(value any) []map[string]any {
+	switch v := value.(type) {
+	case []map[string]any:
+		return v
+	case []any:
+		list := make([]map[string]any, 0, len(v))
+		for _, item := range v {
+			if m, ok := item.(map[string]any); ok {
+				list = append(list, m)
+			}
+		}
+		return list
+	}
+	return nil
+}
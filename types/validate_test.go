@@ -0,0 +1,120 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fields 返回校验错误中的字段列表
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	var names []string
+	for _, fe := range verr.Errors {
+		names = append(names, fe.Field)
+	}
+	return names
+}
+
+func TestAddOptionsValidate(t *testing.T) {
+	assert.NoError(t, AddOptions{UserID: "alice"}.Validate())
+	assert.NoError(t, AddOptions{RunID: "run-1"}.Validate())
+
+	err := AddOptions{
+		Version:          "v3",
+		Timestamp:        -1,
+		CustomCategories: CustomCategories{{CategoryDescription: "no name"}},
+	}.Validate()
+	assert.Equal(t, []string{"version", "user_id", "timestamp", "custom_categories[0]"}, fields(t, err))
+}
+
+func TestQueryOptionsValidate(t *testing.T) {
+	assert.NoError(t, QueryOptions{}.Validate())
+	assert.NoError(t, QueryOptions{Threshold: 1, TopK: 5, Filters: map[string]any{"user_id": "alice"}}.Validate())
+
+	err := QueryOptions{Threshold: 1.5, TopK: -1}.Validate()
+	assert.Equal(t, []string{"threshold", "top_k"}, fields(t, err))
+	assert.EqualError(t, err, "validation failed: threshold: must be between 0 and 1, got 1.5; top_k: must not be negative, got -1")
+}
+
+func TestFilterScopeValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  map[string]any
+		conflict bool
+	}{
+		{"user only", map[string]any{"user_id": "alice"}, false},
+		{"top level", map[string]any{"user_id": "alice", "agent_id": "bot"}, true},
+		{"wildcard", map[string]any{"user_id": SearchWildcard, "agent_id": "bot"}, false},
+		{"ne", map[string]any{"user_id": "alice", "agent_id": map[string]any{"ne": "bot"}}, false},
+		{"in", map[string]any{"user_id": "alice", "agent_id": map[string]any{"in": []any{"bot"}}}, true},
+		{"and", map[string]any{"AND": []any{
+			map[string]any{"user_id": "alice"},
+			map[string]any{"agent_id": "bot"},
+		}}, true},
+		{"nested and", map[string]any{"user_id": "alice", "AND": []map[string]any{
+			{"AND": []map[string]any{{"agent_id": "bot"}}},
+		}}, true},
+		{"or", map[string]any{"OR": []any{
+			map[string]any{"user_id": "alice"},
+			map[string]any{"agent_id": "bot"},
+		}}, false},
+		{"and inside or", map[string]any{"OR": []any{
+			map[string]any{"AND": []any{
+				map[string]any{"user_id": "alice"},
+				map[string]any{"agent_id": "bot"},
+			}},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ListOptions{Filters: tt.filters}.Validate()
+			if tt.conflict {
+				assert.Equal(t, []string{"filters"}, fields(t, err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListOptionsValidate(t *testing.T) {
+	err := ListOptions{
+		Page:       -1,
+		PageSize:   -1,
+		Categories: []string{"work"},
+		Filters:    map[string]any{"categories": map[string]any{"in": []string{"work"}}},
+	}.Validate()
+	assert.Equal(t, []string{"page", "page_size", "categories"}, fields(t, err))
+}
+
+func TestDeleteAllOptionsValidate(t *testing.T) {
+	assert.NoError(t, DeleteAllOptions{AgentID: "bot"}.Validate())
+	assert.Equal(t, []string{"user_id"}, fields(t, DeleteAllOptions{}.Validate()))
+}
+
+func TestFeedbackPayloadValidate(t *testing.T) {
+	assert.NoError(t, FeedbackPayload{MemoryID: "m", Feedback: Positive}.Validate())
+	assert.NoError(t, FeedbackPayload{MemoryID: "m"}.Validate())
+	assert.Equal(t, []string{"memory_id", "feedback"}, fields(t, FeedbackPayload{Feedback: "GREAT"}.Validate()))
+}
+
+func TestWebhookPayloadValidate(t *testing.T) {
+	assert.NoError(t, WebhookPayload{
+		Name:       "hook",
+		URL:        "https://example.com/hook",
+		EventTypes: []WebhookEvent{MemoryAdded},
+	}.Validate())
+
+	err := WebhookPayload{URL: "example.com", EventTypes: []WebhookEvent{"memory_read"}}.Validate()
+	assert.Equal(t, []string{"name", "url", "event_types[0]"}, fields(t, err))
+	assert.Equal(t, []string{"name", "url"}, fields(t, WebhookPayload{}.Validate()))
+}